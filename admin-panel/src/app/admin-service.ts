@@ -1,6 +1,7 @@
 import { ApiService } from "@/services/api-service";
+import { isRetryable } from "@/services/http-service";
 import { DEFAULT_QUESTION, PAGE_LIMIT, PAGE_NUMBER } from "@/shared/constants";
-import type { IQuestion, IQuestionDTO, IQuestionsDTO } from "@/shared/types";
+import type { IPostBody, IQuestion, IQuestionDTO, IQuestionsDTO } from "@/shared/types";
 import { inject, Injectable, signal } from "angular";
 
 Injectable();
@@ -11,6 +12,9 @@ export class AdminPanelService {
   page = signal(PAGE_NUMBER);
   isOpenModal = signal(false);
   editQuestion = signal<IQuestion>(DEFAULT_QUESTION);
+  // Idempotency-Key of a create that has not been answered definitively yet,
+  // reused when the same question is submitted again.
+  private pendingCreate: { key: string; body: string } | null = null;
 
   getQuestions(page?: number) {
     this.apiService
@@ -55,17 +59,26 @@ export class AdminPanelService {
     };
 
     if (!id) {
+      const key = this.createKey(body);
       this.apiService
-        .createQuestion(body)
+        .createQuestion(body, key)
         .then(() => {
+          this.pendingCreate = null;
           this.getQuestions();
           this.isOpenModal(false);
           this.editQuestion.set(DEFAULT_QUESTION);
         })
-        .catch((err: unknown) => err instanceof Error && console.warn(err.message));
+        .catch((err: unknown) => {
+          if (!isRetryable(err)) {
+            this.pendingCreate = null;
+          }
+          if (err instanceof Error) {
+            console.warn(err.message);
+          }
+        });
     } else {
       this.apiService
-        .updateQuestion(id, body)
+        .updateQuestion(id, this.editQuestion().version ?? 0, body)
         .then((resp) => {
           const prev =
             this.questions()?.map((q) => (q.id === resp.id ? this.updateQuestion(resp) : q)) ||
@@ -85,12 +98,21 @@ export class AdminPanelService {
       const newPage = page - 1;
       page = newPage < 1 ? page : newPage;
     }
+    const version = questions?.find((q) => q.id === id)?.version ?? 0;
     this.apiService
-      .deleteQuestion(id)
+      .deleteQuestion(id, version)
       .then(() => this.getQuestions(page))
       .catch((err: unknown) => err instanceof Error && console.warn(err.message));
   }
 
+  private createKey(body: IPostBody) {
+    const json = JSON.stringify(body);
+    if (this.pendingCreate?.body !== json) {
+      this.pendingCreate = { key: crypto.randomUUID(), body: json };
+    }
+    return this.pendingCreate.key;
+  }
+
   private updateData(data: IQuestionsDTO) {
     this.updateQuestions(data.questions);
     this.pages.set(data.total_pages);
@@ -30,11 +30,15 @@ export class ApiService {
     });
   }
 
-  createQuestion(question: IPostBody) {
+  createQuestion(question: IPostBody, idempotencyKey: string) {
     return this.http
-      .post(BASE_URL, {
-        ...question,
-      })
+      .post(
+        BASE_URL,
+        {
+          ...question,
+        },
+        { "Idempotency-Key": idempotencyKey },
+      )
       .then((resp: unknown) => {
         if (!ValidatorService.isValidQuestionDTO(resp)) {
           throw new Error("Invalid answer response");
@@ -43,11 +47,16 @@ export class ApiService {
       });
   }
 
-  updateQuestion(questionId: number, question: IPostBody) {
+  // Merge patch, so fields the panel doesn't edit (e.g. media) are kept.
+  updateQuestion(questionId: number, version: number, question: IPostBody) {
     return this.http
-      .put(`${BASE_URL}/${questionId}`, {
-        ...question,
-      })
+      .patch(
+        `${BASE_URL}/${questionId}`,
+        {
+          ...question,
+        },
+        ifMatch(version),
+      )
       .then((resp: unknown) => {
         if (!ValidatorService.isValidQuestionDTO(resp)) {
           throw new Error("Invalid answer response");
@@ -56,12 +65,18 @@ export class ApiService {
       });
   }
 
-  deleteQuestion(questionId: number) {
-    return this.http.delete(`${BASE_URL}/${questionId}`).then((resp: unknown) => {
-      if (!ValidatorService.isValidQuestionDTO(resp)) {
-        throw new Error("Invalid answer response");
-      }
-      return resp;
-    });
+  deleteQuestion(questionId: number, version: number) {
+    return this.http
+      .delete(`${BASE_URL}/${questionId}`, ifMatch(version))
+      .then((resp: unknown) => {
+        if (!ValidatorService.isValidQuestionDTO(resp)) {
+          throw new Error("Invalid answer response");
+        }
+        return resp;
+      });
   }
 }
+
+function ifMatch(version: number) {
+  return { "If-Match": `"${version}"` };
+}
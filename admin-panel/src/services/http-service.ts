@@ -1,13 +1,31 @@
 import { Injectable } from "angular";
 import type { IPostBody } from "@/shared/types";
 
+export class HttpError extends Error {
+  readonly status: number;
+
+  constructor(response: Response) {
+    super(`HTTP ${response.status}: ${response.statusText}`);
+    this.status = response.status;
+  }
+}
+
+// A request is worth retrying when it never got an answer (network error) or
+// the server could not give a definitive one yet (in flight or failed).
+export function isRetryable(error: unknown) {
+  if (error instanceof HttpError) {
+    return error.status === 409 || error.status >= 500;
+  }
+  return error instanceof TypeError;
+}
+
 Injectable();
 export class HttpService {
   get(url: string) {
     return fetch(url)
       .then((response) => {
         if (!response.ok) {
-          throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+          throw new HttpError(response);
         }
         return response.json();
       })
@@ -17,15 +35,15 @@ export class HttpService {
       });
   }
 
-  post(url: string, body: IPostBody) {
+  post(url: string, body: IPostBody, headers: Record<string, string> = {}) {
     return fetch(url, {
       method: "POST",
-      headers: { "Content-Type": "application/json" },
+      headers: { "Content-Type": "application/json", ...headers },
       body: JSON.stringify(body),
     })
       .then((response) => {
         if (!response.ok) {
-          throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+          throw new HttpError(response);
         }
         return response.json();
       })
@@ -35,15 +53,15 @@ export class HttpService {
       });
   }
 
-  put(url: string, body: IPostBody) {
+  put(url: string, body: IPostBody, headers: Record<string, string> = {}) {
     return fetch(url, {
       method: "PUT",
-      headers: { "Content-Type": "application/json" },
+      headers: { "Content-Type": "application/json", ...headers },
       body: JSON.stringify(body),
     })
       .then((response) => {
         if (!response.ok) {
-          throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+          throw new HttpError(response);
         }
         return response.json();
       })
@@ -53,13 +71,32 @@ export class HttpService {
       });
   }
 
-  delete(url: string) {
+  patch(url: string, body: IPostBody, headers: Record<string, string> = {}) {
+    return fetch(url, {
+      method: "PATCH",
+      headers: { "Content-Type": "application/merge-patch+json", ...headers },
+      body: JSON.stringify(body),
+    })
+      .then((response) => {
+        if (!response.ok) {
+          throw new HttpError(response);
+        }
+        return response.json();
+      })
+      .catch((error) => {
+        console.error("HTTP PATCH Error:", error);
+        throw error;
+      });
+  }
+
+  delete(url: string, headers: Record<string, string> = {}) {
     return fetch(url, {
       method: "DELETE",
+      headers,
     })
       .then((response) => {
         if (!response.ok) {
-          throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+          throw new HttpError(response);
         }
         return response.json();
       })
@@ -37,7 +37,9 @@ export class ValidatorService {
       Array.isArray(question.options) &&
       question.options.every((opt) => typeof opt === "string") &&
       "correct_answer" in question &&
-      typeof question.correct_answer === "number"
+      typeof question.correct_answer === "number" &&
+      "version" in question &&
+      typeof question.version === "number"
     );
   }
 }
@@ -3,6 +3,7 @@ export interface IQuestionDTO {
   text: string;
   options: Array<string>;
   correct_answer: string;
+  version: number;
 }
 
 export interface IPostBody {
@@ -20,6 +21,7 @@ export interface IQuestionsDTO {
 
 export interface IQuestion {
   id: number | null;
+  version?: number;
   text: string;
   options: Array<string>;
   answer: {
@@ -1,14 +1,18 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	_ "quiz_backend/docs"
+	"quiz_backend/internal/health"
+	"quiz_backend/internal/media"
 	"quiz_backend/internal/quiz"
 	"quiz_backend/internal/session"
-	"quiz_backend/models"
+	"quiz_backend/pkg/config"
 	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/metrics"
 	"quiz_backend/pkg/middleware"
 
 	"github.com/joho/godotenv"
@@ -23,17 +27,27 @@ import (
 func main() {
 	_ = godotenv.Load()
 
+	log := logger.New(os.Stdout)
+	slog.SetDefault(log)
+
+	cfg := config.Load()
+
 	conn := db.ConnectDb()
 
-	conn.Migrator().DropTable(&models.Question{}, &models.UserSession{})
-	if err := conn.AutoMigrate(&models.Question{}, &models.UserSession{}); err != nil {
-		log.Fatal("Migration failed:", err)
+	conn.Migrator().DropTable(db.Tables...)
+	if err := conn.AutoMigrate(db.Tables...); err != nil {
+		log.Error("migration failed", "err", err)
+		os.Exit(1)
+	}
+
+	fullText, err := conn.SetupFullTextSearch()
+	if err != nil {
+		log.Error("full-text search setup failed", "err", err)
+		os.Exit(1)
 	}
 
-	if err := conn.SeedQuiz(); err != nil {
-		log.Println("Warning: Seed failed (run once):", err)
-	} else {
-		log.Println("Database seeded")
+	if err := conn.SeedQuiz(cfg.DuplicateThreshold); err != nil {
+		log.Warn("seed failed (run once)", "err", err)
 	}
 
 	mux := http.NewServeMux()
@@ -49,24 +63,101 @@ func main() {
 	})
 
 	repo := quiz.NewQuizRepository(conn)
+	if fullText {
+		repo.UseFullTextSearch()
+	}
 
 	sessionSvc := session.NewService(repo)
-	quizSvc := quiz.NewQuizService(repo)
+	locales, err := quiz.ParseLocales(cfg.DefaultLocale, cfg.Locales)
+	if err != nil {
+		log.Error("invalid locale configuration", "err", err)
+		os.Exit(1)
+	}
+	feedback, err := quiz.ParseFeedback(cfg.FeedbackPolicy, cfg.FeedbackCloseDate)
+	if err != nil {
+		log.Error("invalid feedback configuration", "err", err)
+		os.Exit(1)
+	}
+	scoringParams := quiz.ScoringParams{
+		BasePoints:    cfg.ScoreBasePoints,
+		SpeedBonus:    cfg.ScoreSpeedBonus,
+		StreakStep:    cfg.ScoreStreakStep,
+		MaxMultiplier: cfg.ScoreStreakMax,
+		WrongPenalty:  cfg.ScoreWrongPenalty,
+	}
+	scoring, err := quiz.NewScoringPolicy(cfg.ScoringPolicy, scoringParams)
+	if err != nil {
+		log.Error("invalid scoring configuration", "err", err)
+		os.Exit(1)
+	}
+	navigation, err := quiz.ParseNavigation(cfg.NavigationMode, cfg.NavigationTimer)
+	if err != nil {
+		log.Error("invalid navigation configuration", "err", err)
+		os.Exit(1)
+	}
+	quizSvc := quiz.NewQuizService(repo, quiz.Settings{
+		Locales:       locales,
+		Feedback:      feedback,
+		Scoring:       scoring,
+		ScoringParams: scoringParams,
+		Lifelines: quiz.Lifelines{
+			Hints:             cfg.LifelineHints,
+			FiftyFifty:        cfg.LifelineFiftyFifty,
+			Skips:             cfg.LifelineSkips,
+			HintPenalty:       cfg.HintPenalty,
+			FiftyFiftyPenalty: cfg.FiftyFiftyPenalty,
+		},
+		Navigation:     navigation,
+		RoundTimeLimit: cfg.RoundTimeLimit,
+		Pausing:        quiz.Pausing{Limit: cfg.PausesPerRound},
+	})
+
+	mediaStorage, err := media.NewLocalStorage(cfg.MediaDir)
+	if err != nil {
+		log.Error("media storage unavailable", "dir", cfg.MediaDir, "err", err)
+		os.Exit(1)
+	}
+	mediaRepo := media.NewMediaRepository(conn)
+	media.NewMediaHandler(mux, media.MediaHandlerDeps{
+		MediaRepository: mediaRepo,
+		Storage:         mediaStorage,
+		MaxSize:         cfg.MediaMaxSize,
+	})
 
 	quiz.NewQuizHandler(mux, quiz.QuizHandlerDeps{
 		QuizRepository: repo,
 		SessionService: sessionSvc,
 		QuizService:    quizSvc,
+		Media:          mediaRepo,
+		Duplicates: quiz.DuplicatePolicy{
+			Threshold: cfg.DuplicateThreshold,
+			Reject:    cfg.RejectDuplicates,
+		},
 	})
 
-	chain := middleware.CreateMiddlewareChain(middleware.CorsMiddleware)
+	chain := middleware.CreateMiddlewareChain(
+		middleware.RequestID,
+		middleware.Logging(log, session.CookieName),
+		middleware.Metrics,
+		middleware.CorsMiddleware,
+		// Media uploads are the largest bodies the API accepts.
+		middleware.Idempotency(db.NewIdempotencyStore(conn), cfg.IdempotencyTTL, media.MaxRequestSize(cfg.MediaMaxSize), session.CookieName),
+	)
+
+	// Probes and metrics are served outside the CORS-wrapped API routes.
+	root := http.NewServeMux()
+	health.NewHealthHandler(root, health.HealthHandlerDeps{Db: conn})
+	root.Handle("GET /metrics", metrics.Handler())
+	root.Handle("/", chain(mux))
 
 	server := &http.Server{
 		Addr:    ":5000",
-		Handler: chain(mux),
+		Handler: root,
 	}
 
-	fmt.Println("Server running: http://localhost:5000")
-	fmt.Println("Swagger UI:    http://localhost:5000/swagger/")
-	log.Fatal(server.ListenAndServe())
+	log.Info("server running", "addr", "http://localhost:5000", "swagger", "http://localhost:5000/swagger/")
+	if err := server.ListenAndServe(); err != nil {
+		log.Error("server stopped", "err", err)
+		os.Exit(1)
+	}
 }
@@ -15,8 +15,106 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/media": {
+            "post": {
+                "description": "Accepts PNG, JPEG, GIF, WebP, MP3, WAV and Ogg, detected from the content rather than the declared type. Reference the returned key from a question's media or option_media.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Upload an image or audio file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Media file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MediaDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{key}": {
+            "get": {
+                "description": "Media never changes once uploaded, so responses may be cached indefinitely. Supports conditional and range requests.",
+                "produces": [
+                    "image/png",
+                    "image/jpeg",
+                    "image/gif",
+                    "image/webp",
+                    "audio/mpeg",
+                    "audio/wav",
+                    "audio/ogg"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Download media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Media key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/questions": {
             "get": {
+                "description": "Pages by offset (page) or, when cursor is set, by keyset from next_cursor/prev_cursor of a previous response. A cursor carries its own sort and order.\nsearch matches question text and options: words match as prefixes, \"quoted phrases\" exactly. Results are ranked by relevance unless another sort is given (relevance pages have no cursors) and carry highlighted fragments.",
                 "consumes": [
                     "application/json"
                 ],
@@ -26,14 +124,75 @@ const docTemplate = `{
                 "tags": [
                     "questions"
                 ],
-                "summary": "List questions with pagination and search",
+                "summary": "List questions with pagination, sorting and filters",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search in text",
+                        "description": "Full-text search in text and options",
                         "name": "search",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Search in option text",
+                        "name": "option",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "updated_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "id",
+                            "created_at",
+                            "updated_at",
+                            "text"
+                        ],
+                        "type": "string",
+                        "default": "id",
+                        "description": "Sort key (relevance is the default with search)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -55,10 +214,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.AdminPanelQuestionsDTO"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
                     }
                 }
             },
             "post": {
+                "description": "Existing questions scoring above the duplicate threshold are returned in possible_duplicates, or reject the create with 409 when the server runs with DUPLICATE_MODE=reject (unless force=true).",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,22 +244,71 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.QuestionDataDTO"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create even if near-duplicates exist",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdminPanelQuestionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/questions/duplicates": {
+            "get": {
+                "description": "Lists every pair of questions whose similarity (TF-IDF cosine of the text and overlap of the options) reaches threshold. Each pair appears once, under the question with the lower ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "questions"
+                ],
+                "summary": "Report near-duplicate questions",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Minimum score between 0 and 1 (defaults to the server setting)",
+                        "name": "threshold",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.AdminPanelQuestionDTO"
+                            "$ref": "#/definitions/models.DuplicateReportDTO"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -118,6 +333,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -125,29 +346,33 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.AdminPanelQuestionDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current question version"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             },
             "put": {
+                "description": "Full replacement: text, options and correct_answer are all required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -157,7 +382,7 @@ const docTemplate = `{
                 "tags": [
                     "questions"
                 ],
-                "summary": "Update question by ID",
+                "summary": "Replace question by ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -166,6 +391,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag of the question being replaced",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Question data",
                         "name": "question",
@@ -186,19 +418,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
@@ -221,6 +465,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the question being deleted",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -233,44 +484,63 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/quiz/answer": {
-            "post": {
+            },
+            "patch": {
+                "description": "JSON Merge Patch (RFC 7386): only the members present in the body change, null removes a member. The merged question is validated as a whole.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "questions"
                 ],
-                "summary": "Submit answer for current question",
+                "summary": "Partially update question by ID",
                 "parameters": [
                     {
-                        "description": "Answer data",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the question being patched",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch",
+                        "name": "question",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AnswerRequest"
+                            "$ref": "#/definitions/models.QuestionDataDTO"
                         }
                     }
                 ],
@@ -278,154 +548,1317 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.AnswerResponse"
+                            "$ref": "#/definitions/models.AdminPanelQuestionDTO"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/quiz/check-session": {
+        "/questions/{id}/translations": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "translations"
+                ],
+                "summary": "List translations of a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Check if already have a session",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionStats"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TranslationDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.Problem"
                         }
                     }
                 }
             }
         },
-        "/quiz/start": {
-            "get": {
+        "/questions/{id}/translations/{locale}": {
+            "put": {
+                "description": "options must be translated one to one, in the question's order. The question's format applies to the translation too. Without a hint the hint lifeline is unavailable in this locale.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "quiz"
+                    "translations"
                 ],
-                "summary": "Start or resume quiz session",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.StartResponse"
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "models.AdminPanelQuestionDTO": {
-            "type": "object",
-            "properties": {
-                "correct_answer": {
+                "summary": "Create or replace a translation of a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "BCP 47 language tag",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translated text and options",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TranslationDataDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TranslationDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "translations"
+                ],
+                "summary": "Delete a translation of a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "BCP 47 language tag",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/answer": {
+            "post": {
+                "description": "question_idx and question_id must identify the current question. Resubmitting an already answered question returns its original result. In free-navigation rounds the answer is saved as a draft, which may be changed until the round is finished, and the next open question is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Submit answer for current question",
+                "parameters": [
+                    {
+                        "description": "Answer data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AnswerRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages for the next question",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AnswerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/check-session": {
+            "get": {
+                "description": "Returns the session of the cookie, creating one if there is none. A finished round stays on the session, and reviewable, until the next round is started.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Check if already have a session",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/finish": {
+            "post": {
+                "description": "Scores the drafts of every question and ends the round. Unanswered questions count as skipped. Results are shown according to the round's feedback policy.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Submit a free-navigation round",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/flag": {
+            "post": {
+                "description": "Marks the question at question_idx for review, or clears the mark when flagged is false.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Flag a question of a free-navigation round",
+                "parameters": [
+                    {
+                        "description": "Question and flag",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FlagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/lifeline": {
+            "post": {
+                "description": "hint returns the question's hint, fifty_fifty removes two wrong options (indexes in the player's order), skip moves on to the next question without points. Each kind is limited per round; using hint or fifty_fifty again on the same question repeats its effect for free. Hints and 50/50s take a share of the points off a correct answer. Once the question or the round has run out of time the timeout is recorded and the lifeline refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Use a lifeline on the current question",
+                "parameters": [
+                    {
+                        "description": "Lifeline and current question",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LifelineRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages for the next question",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LifelineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/navigate": {
+            "post": {
+                "description": "Shows the question at question_idx. Only rounds in free navigation mode allow it, and under the question timer not once the question's time is up.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Move to a question of a free-navigation round",
+                "parameters": [
+                    {
+                        "description": "Question to show",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NavigateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/pause": {
+            "post": {
+                "description": "Stops the question and round timers until the round is resumed; the current question is withheld meanwhile. How often a round may be paused is configured per quiz. Pausing a paused round changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Pause the running round",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StartResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/resume": {
+            "post": {
+                "description": "Restarts the timers where they stopped and returns the current question as GET /quiz/start does. Resuming a running round just returns it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Resume a paused round",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Preferred languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StartResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/review": {
+            "get": {
+                "description": "Lists every answered question of the session's latest round with the submitted and the correct option (in the player's order). Available according to the round's feedback policy: immediate at any time, end_of_round once the round is finished, after_close_date once the exam closed, never not at all.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Review the latest round",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Preferred languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/start": {
+            "get": {
+                "description": "The question is returned in the session locale if one was chosen, else in the best match for Accept-Language, else in the default locale. While the round is paused no question is returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Start or resume quiz session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Remember this locale (BCP 47) for the session",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/time": {
+            "get": {
+                "description": "Returns the current server time so clients can estimate the offset of their clock, e.g. as server_time minus the midpoint of the request's round trip, and count down to question deadlines accurately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quiz"
+                ],
+                "summary": "Server clock",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServerTimeDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/translations/missing": {
+            "get": {
+                "description": "Covers the configured locales plus every locale any question is translated into, or only the given locale. A translation is stale when its question changed after it was written.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "translations"
+                ],
+                "summary": "Report missing and outdated translations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only report this locale",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MissingTranslationsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AdminPanelQuestionDTO": {
+            "type": "object",
+            "properties": {
+                "correct_answer": {
+                    "type": "integer"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "highlight": {
+                    "description": "Set in search results: matched fragments as HTML with \u003cmark\u003e tags.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SearchHighlight"
+                        }
+                    ]
+                },
+                "hint": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "media": {
+                    "type": "string"
+                },
+                "option_media": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "possible_duplicates": {
+                    "description": "Set on create: existing questions the new one closely resembles.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateMatchDTO"
+                    }
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reference"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
                     "type": "integer"
                 },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AdminPanelQuestionsDTO": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "pass as ?cursor= for the following page",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "0 when paging by cursor",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "description": "pass as ?cursor= for the preceding page",
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdminPanelQuestionDTO"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AnswerRequest": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "integer"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "question_idx": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AnswerResponse": {
+            "type": "object",
+            "properties": {
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer_idx": {
+                    "type": "integer"
+                },
+                "current_index": {
+                    "type": "integer"
+                },
+                "explanation_html": {
+                    "description": "why the correct answer is correct, sanitised HTML",
+                    "type": "string"
+                },
+                "feedback_withheld": {
+                    "type": "boolean"
+                },
+                "has_active_game": {
+                    "type": "boolean"
+                },
+                "navigation": {
+                    "description": "Set in free-navigation rounds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NavigationDTO"
+                        }
+                    ]
+                },
+                "next_question": {
+                    "$ref": "#/definitions/models.QuestionDTO"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "points": {
+                    "description": "what this answer scored",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScoreBreakdown"
+                        }
+                    ]
+                },
+                "reason": {
+                    "description": "\"timeout\", \"wrong_answer\", \"correct\", \"recorded\"",
+                    "type": "string"
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reference"
+                    }
+                },
+                "round_deadline": {
+                    "type": "string"
+                },
+                "round_time_left": {
+                    "description": "Seconds left until the round deadline, for rounds with one. The\ndeadline itself is omitted while the round is paused.",
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/models.ScoreDTO"
+                },
+                "server_time": {
+                    "description": "When the response was made, to compare deadlines with the client clock.",
+                    "type": "string"
+                },
+                "total_correct": {
+                    "type": "integer"
+                },
+                "total_incorrect": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DuplicateMatchDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "option_score": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "text_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DuplicateReportDTO": {
+            "type": "object",
+            "properties": {
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateReportEntryDTO"
+                    }
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DuplicateReportEntryDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateMatchDTO"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FlagRequest": {
+            "type": "object",
+            "properties": {
+                "flagged": {
+                    "type": "boolean"
+                },
+                "question_idx": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LifelineRequest": {
+            "type": "object",
+            "required": [
+                "lifeline"
+            ],
+            "properties": {
+                "lifeline": {
+                    "type": "string",
+                    "enum": [
+                        "hint",
+                        "fifty_fifty",
+                        "skip"
+                    ]
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "question_idx": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LifelineResponse": {
+            "type": "object",
+            "properties": {
+                "current_index": {
+                    "type": "integer"
+                },
+                "has_active_game": {
+                    "type": "boolean"
+                },
+                "hint_html": {
+                    "description": "hint: sanitised HTML",
+                    "type": "string"
+                },
+                "lifeline": {
+                    "type": "string"
+                },
+                "lifelines_left": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "navigation": {
+                    "description": "Set in free-navigation rounds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NavigationDTO"
+                        }
+                    ]
+                },
+                "next_question": {
+                    "description": "skip: the question after the skipped one",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.QuestionDTO"
+                        }
+                    ]
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "removed_options": {
+                    "description": "fifty_fifty: indexes in the player's order",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "round_deadline": {
+                    "type": "string"
+                },
+                "round_time_left": {
+                    "description": "Seconds left until the round deadline, for rounds with one. The\ndeadline itself is omitted while the round is paused.",
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/models.ScoreDTO"
+                },
+                "server_time": {
+                    "description": "When the response was made, to compare deadlines with the client clock.",
+                    "type": "string"
+                },
+                "total_correct": {
+                    "type": "integer"
+                },
+                "total_incorrect": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MediaDTO": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MissingTranslationsDTO": {
+            "type": "object",
+            "properties": {
+                "default_locale": {
+                    "type": "string"
+                },
+                "locales": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionTranslationStatus"
+                    }
+                }
+            }
+        },
+        "models.NavigateRequest": {
+            "type": "object",
+            "properties": {
+                "question_idx": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NavigationDTO": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NavigationItemDTO"
+                    }
+                },
+                "timer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NavigationItemDTO": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "description": "draft in the player's option order, -1 for none",
+                    "type": "integer"
+                },
+                "answered": {
+                    "type": "boolean"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "locked": {
+                    "description": "its time ran out, the draft is final",
+                    "type": "boolean"
+                },
+                "question_idx": {
+                    "type": "integer"
+                },
+                "time_left": {
+                    "description": "seconds, for the question timer",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.QuestionDTO": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "description": "when the answer is due; answers more than TimeGracePeriod later time out",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "format of Text and Options",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "locale": {
+                    "description": "locale of Text and Options",
+                    "type": "string"
+                },
+                "media_url": {
+                    "type": "string"
+                },
+                "option_media_urls": {
+                    "description": "\"\" for options without media",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "options": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "options_html": {
+                    "description": "Options rendered to sanitised inline HTML",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "text": {
                     "type": "string"
+                },
+                "text_html": {
+                    "description": "Text rendered to sanitised HTML",
+                    "type": "string"
+                },
+                "time_limit": {
+                    "description": "seconds until Deadline",
+                    "type": "integer"
                 }
             }
         },
-        "models.AdminPanelQuestionsDTO": {
+        "models.QuestionDataDTO": {
             "type": "object",
+            "required": [
+                "options",
+                "text"
+            ],
             "properties": {
-                "page": {
+                "correct_answer": {
                     "type": "integer"
                 },
-                "questions": {
+                "explanation": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "plain",
+                        "markdown"
+                    ]
+                },
+                "hint": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "media": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "option_media": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.AdminPanelQuestionDTO"
+                        "type": "string"
                     }
                 },
-                "total_count": {
-                    "type": "integer"
+                "options": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "total_pages": {
-                    "type": "integer"
+                "references": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/models.Reference"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "weight": {
+                    "description": "0 means 1",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0
                 }
             }
         },
-        "models.AnswerRequest": {
+        "models.QuestionTranslationStatus": {
             "type": "object",
             "properties": {
-                "answer": {
+                "id": {
                     "type": "integer"
                 },
-                "question_id": {
-                    "type": "integer"
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "question_idx": {
-                    "type": "integer"
+                "stale": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
                 }
             }
         },
-        "models.AnswerResponse": {
+        "models.Reference": {
             "type": "object",
+            "required": [
+                "url"
+            ],
             "properties": {
-                "correct": {
-                    "type": "boolean"
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
                 },
-                "correct_answer_idx": {
-                    "type": "integer"
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "models.ReviewDTO": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewItemDTO"
+                    }
                 },
-                "current_index": {
-                    "type": "integer"
+                "feedback_policy": {
+                    "type": "string"
                 },
                 "has_active_game": {
                     "type": "boolean"
                 },
-                "next_question": {
-                    "$ref": "#/definitions/models.QuestionDTO"
-                },
-                "reason": {
-                    "description": "\"timeout\", \"wrong_answer\", \"correct\"",
-                    "type": "string"
+                "score": {
+                    "$ref": "#/definitions/models.ScoreDTO"
                 },
                 "total_correct": {
                     "type": "integer"
@@ -435,43 +1868,129 @@ const docTemplate = `{
                 }
             }
         },
-        "models.QuestionDTO": {
+        "models.ReviewItemDTO": {
             "type": "object",
             "properties": {
-                "id": {
+                "answer": {
+                    "type": "integer"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer_idx": {
                     "type": "integer"
                 },
+                "explanation_html": {
+                    "type": "string"
+                },
                 "options": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "points": {
+                    "$ref": "#/definitions/models.ScoreBreakdown"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "question_idx": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reference"
+                    }
+                },
                 "text": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ScoreBreakdown": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "description": "base points times question weight",
+                    "type": "integer"
                 },
-                "time_limit": {
+                "penalty": {
+                    "description": "negative marking for a wrong answer",
+                    "type": "integer"
+                },
+                "points": {
+                    "description": "Base + SpeedBonus + StreakBonus - Penalty",
+                    "type": "integer"
+                },
+                "speed_bonus": {
+                    "description": "for answering early",
+                    "type": "integer"
+                },
+                "streak_bonus": {
+                    "description": "what the multiplier added",
                     "type": "integer"
+                },
+                "streak_multiplier": {
+                    "description": "applied to base and speed bonus",
+                    "type": "number"
                 }
             }
         },
-        "models.QuestionDataDTO": {
+        "models.ScoreDTO": {
             "type": "object",
             "properties": {
-                "correct_answer": {
+                "base": {
+                    "type": "integer"
+                },
+                "penalty": {
+                    "type": "integer"
+                },
+                "policy": {
+                    "description": "scoring policy the points were given by",
+                    "type": "string"
+                },
+                "speed_bonus": {
+                    "type": "integer"
+                },
+                "streak": {
+                    "description": "current run of correct answers",
+                    "type": "integer"
+                },
+                "streak_bonus": {
                     "type": "integer"
                 },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SearchHighlight": {
+            "type": "object",
+            "properties": {
                 "options": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                    "type": "string"
                 },
                 "text": {
                     "type": "string"
                 }
             }
         },
+        "models.ServerTimeDTO": {
+            "type": "object",
+            "properties": {
+                "server_time": {
+                    "type": "string"
+                },
+                "unix_ms": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.SessionStats": {
             "type": "object",
             "properties": {
@@ -481,6 +2000,31 @@ const docTemplate = `{
                 "has_active_game": {
                     "type": "boolean"
                 },
+                "navigation": {
+                    "description": "Set in free-navigation rounds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NavigationDTO"
+                        }
+                    ]
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "round_deadline": {
+                    "type": "string"
+                },
+                "round_time_left": {
+                    "description": "Seconds left until the round deadline, for rounds with one. The\ndeadline itself is omitted while the round is paused.",
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/models.ScoreDTO"
+                },
+                "server_time": {
+                    "description": "When the response was made, to compare deadlines with the client clock.",
+                    "type": "string"
+                },
                 "total_correct": {
                     "type": "integer"
                 },
@@ -498,9 +2042,43 @@ const docTemplate = `{
                 "has_active_game": {
                     "type": "boolean"
                 },
+                "lifelines_left": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "navigation": {
+                    "description": "Set in free-navigation rounds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.NavigationDTO"
+                        }
+                    ]
+                },
                 "next_question": {
                     "$ref": "#/definitions/models.QuestionDTO"
                 },
+                "paused": {
+                    "type": "boolean"
+                },
+                "pauses_left": {
+                    "type": "integer"
+                },
+                "round_deadline": {
+                    "type": "string"
+                },
+                "round_time_left": {
+                    "description": "Seconds left until the round deadline, for rounds with one. The\ndeadline itself is omitted while the round is paused.",
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/models.ScoreDTO"
+                },
+                "server_time": {
+                    "description": "When the response was made, to compare deadlines with the client clock.",
+                    "type": "string"
+                },
                 "total_correct": {
                     "type": "integer"
                 },
@@ -508,6 +2086,109 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "models.TranslationDTO": {
+            "type": "object",
+            "properties": {
+                "hint": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set when the question changed after it was translated.",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TranslationDataDTO": {
+            "type": "object",
+            "required": [
+                "options",
+                "text"
+            ],
+            "properties": {
+                "hint": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "response.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "description": "Duplicates lists the existing questions a rejected one is too close to.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateMatchDTO"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"quiz_backend/models"
+	"quiz_backend/pkg/buildinfo"
+	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/response"
+	"time"
+)
+
+const readyTimeout = 2 * time.Second
+
+type HealthHandlerDeps struct {
+	Db *db.Db
+}
+
+type HealthHandler struct {
+	db *db.Db
+}
+
+type ReadyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func NewHealthHandler(mux *http.ServeMux, deps HealthHandlerDeps) {
+	h := &HealthHandler{
+		db: deps.Db,
+	}
+
+	mux.HandleFunc("GET /healthz", h.Healthz())
+	mux.HandleFunc("GET /readyz", h.Readyz())
+	mux.HandleFunc("GET /version", h.Version())
+}
+
+// Healthz reports that the process is up and serving requests.
+func (h *HealthHandler) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, map[string]string{"status": "ok"})
+	}
+}
+
+// Readyz reports whether the database is reachable, the schema is migrated
+// and there are questions to play. It answers 503 when any check fails.
+func (h *HealthHandler) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+
+		resp := ReadyResponse{Status: "ok", Checks: map[string]string{}}
+		fail := func(check string, err error) {
+			resp.Status = "unavailable"
+			resp.Checks[check] = err.Error()
+			logger.FromContext(r.Context()).Warn("readiness check failed", "check", check, "err", err)
+		}
+
+		if err := h.db.Ping(ctx); err != nil {
+			fail("database", err)
+			response.JsonResp(w, resp, http.StatusServiceUnavailable)
+			return
+		}
+		resp.Checks["database"] = "ok"
+
+		if err := h.db.CheckMigrations(); err != nil {
+			fail("migrations", err)
+		} else {
+			resp.Checks["migrations"] = "ok"
+		}
+
+		var count int64
+		if err := h.db.WithContext(ctx).Model(&models.Question{}).Count(&count).Error; err != nil {
+			fail("questions", err)
+		} else if count == 0 {
+			resp.Status = "unavailable"
+			resp.Checks["questions"] = "question pool is empty"
+		} else {
+			resp.Checks["questions"] = "ok"
+		}
+
+		if resp.Status != "ok" {
+			response.JsonResp(w, resp, http.StatusServiceUnavailable)
+			return
+		}
+		response.OK(w, resp)
+	}
+}
+
+// Version returns the commit, build time and Go version of the running binary.
+func (h *HealthHandler) Version() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.OK(w, buildinfo.Get())
+	}
+}
@@ -0,0 +1,187 @@
+package media
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"quiz_backend/models"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/response"
+)
+
+// multipartOverhead is the room left for multipart headers and boundaries on
+// top of the file size limit.
+const multipartOverhead = 64 << 10
+
+// MaxRequestSize is the largest upload request body accepted for files of up
+// to maxSize bytes.
+func MaxRequestSize(maxSize int64) int64 {
+	return maxSize + multipartOverhead
+}
+
+// allowedTypes maps the sniffed content types that may be uploaded to the
+// type they are served with and the file extension of their key. SVG is left
+// out on purpose: it can carry scripts.
+var allowedTypes = map[string]struct{ contentType, ext string }{
+	"image/png":       {"image/png", ".png"},
+	"image/jpeg":      {"image/jpeg", ".jpg"},
+	"image/gif":       {"image/gif", ".gif"},
+	"image/webp":      {"image/webp", ".webp"},
+	"audio/mpeg":      {"audio/mpeg", ".mp3"},
+	"audio/wave":      {"audio/wav", ".wav"},
+	"application/ogg": {"audio/ogg", ".ogg"},
+}
+
+type MediaHandlerDeps struct {
+	MediaRepository *MediaRepository
+	Storage         Storage
+	MaxSize         int64
+}
+
+type MediaHandler struct {
+	repo    *MediaRepository
+	storage Storage
+	maxSize int64
+}
+
+func NewMediaHandler(mux *http.ServeMux, deps MediaHandlerDeps) {
+	h := &MediaHandler{
+		repo:    deps.MediaRepository,
+		storage: deps.Storage,
+		maxSize: deps.MaxSize,
+	}
+
+	mux.HandleFunc("POST /api/v1/media", h.Upload())
+	mux.HandleFunc("GET /api/v1/media/{key}", h.Serve())
+}
+
+// Upload godoc
+// @Summary      Upload an image or audio file
+// @Description  Accepts PNG, JPEG, GIF, WebP, MP3, WAV and Ogg, detected from the content rather than the declared type. Reference the returned key from a question's media or option_media.
+// @Tags         media
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "Media file"
+// @Success      201 {object} models.MediaDTO
+// @Failure      400 {object} response.Problem
+// @Failure      413 {object} response.Problem
+// @Failure      415 {object} response.Problem
+// @Router       /media [post]
+func (h *MediaHandler) Upload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestSize(h.maxSize))
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.PayloadTooLarge(w, "File exceeds the upload limit")
+				return
+			}
+			response.BadRequest(w, "Expected a multipart form with a file field")
+			return
+		}
+		defer file.Close()
+
+		if header.Size > h.maxSize {
+			response.PayloadTooLarge(w, "File exceeds the upload limit")
+			return
+		}
+
+		sniff := make([]byte, 512)
+		n, err := io.ReadFull(file, sniff)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			response.BadRequest(w, "File is empty")
+			return
+		}
+		kind, ok := allowedTypes[http.DetectContentType(sniff[:n])]
+		if !ok {
+			response.UnsupportedMediaType(w, "Only PNG, JPEG, GIF, WebP, MP3, WAV and Ogg files are accepted")
+			return
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			response.InternalError(w, "Can't store file")
+			return
+		}
+
+		key, err := newKey(kind.ext)
+		if err != nil {
+			response.InternalError(w, "Can't store file")
+			return
+		}
+
+		hash := sha256.New()
+		if err := h.storage.Put(r.Context(), key, io.TeeReader(file, hash)); err != nil {
+			logger.FromContext(r.Context()).Error("store media", "key", key, "err", err)
+			response.InternalError(w, "Can't store file")
+			return
+		}
+
+		m := &models.Media{
+			Key:         key,
+			ContentType: kind.contentType,
+			Size:        header.Size,
+			SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		}
+		if err := h.repo.Create(m); err != nil {
+			if delErr := h.storage.Delete(r.Context(), key); delErr != nil {
+				logger.FromContext(r.Context()).Error("remove orphaned media", "key", key, "err", delErr)
+			}
+			response.FromError(w, r, err, "Media")
+			return
+		}
+
+		dto := response.ToMediaDTO(m)
+		w.Header().Set("Location", dto.URL)
+		response.Created(w, dto)
+	}
+}
+
+// Serve godoc
+// @Summary      Download media
+// @Description  Media never changes once uploaded, so responses may be cached indefinitely. Supports conditional and range requests.
+// @Tags         media
+// @Produce      image/png,image/jpeg,image/gif,image/webp,audio/mpeg,audio/wav,audio/ogg
+// @Param        key  path  string  true  "Media key"
+// @Success      200 {file} file
+// @Success      206 {file} file
+// @Failure      404 {object} response.Problem
+// @Router       /media/{key} [get]
+func (h *MediaHandler) Serve() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, err := h.repo.GetByKey(r.PathValue("key"))
+		if err != nil {
+			response.FromError(w, r, err, "Media")
+			return
+		}
+
+		f, err := h.storage.Open(r.Context(), m.Key)
+		if errors.Is(err, ErrNotFound) {
+			logger.FromContext(r.Context()).Error("media file missing from storage", "key", m.Key)
+			response.NotFound(w, "Media not found")
+			return
+		}
+		if err != nil {
+			response.FromError(w, r, err, "Media")
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", m.ContentType)
+		w.Header().Set("ETag", `"`+m.SHA256+`"`)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, "", m.CreatedAt, f)
+	}
+}
+
+// newKey returns a random, unguessable key with the given extension.
+func newKey(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b) + ext, nil
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"quiz_backend/models"
+	"quiz_backend/pkg/db"
+)
+
+type MediaRepository struct {
+	Database *db.Db
+}
+
+func NewMediaRepository(database *db.Db) *MediaRepository {
+	return &MediaRepository{Database: database}
+}
+
+func (repo *MediaRepository) Create(m *models.Media) error {
+	return repo.Database.DB.Create(m).Error
+}
+
+func (repo *MediaRepository) GetByKey(key string) (*models.Media, error) {
+	var m models.Media
+	if err := repo.Database.DB.Where("key = ?", key).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// MissingKeys returns the keys among keys that no uploaded media has.
+// Empty keys are ignored.
+func (repo *MediaRepository) MissingKeys(keys []string) ([]string, error) {
+	want := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k != "" {
+			want = append(want, k)
+		}
+	}
+	if len(want) == 0 {
+		return nil, nil
+	}
+
+	var found []string
+	if err := repo.Database.DB.Model(&models.Media{}).Where("key IN ?", want).Pluck("key", &found).Error; err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(found))
+	for _, k := range found {
+		known[k] = true
+	}
+
+	var missing []string
+	for _, k := range want {
+		if !known[k] {
+			missing = append(missing, k)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,103 @@
+package quiz
+
+import (
+	"math"
+	"quiz_backend/models"
+	"quiz_backend/pkg/metrics"
+	"time"
+)
+
+// roundDeadline returns when a round starting at now must be finished, or nil
+// when it has no time limit. Free rounds on the round timer without a
+// configured limit get the time of all their questions together.
+func (s *QuizService) roundDeadline(session *models.UserSession, now time.Time) *time.Time {
+	limit := s.settings.RoundTimeLimit
+	if limit == 0 && freeNavigation(session) && session.NavigationTimer == models.TimerRound {
+		limit = time.Duration(len(session.Questions)*models.QuestionTimeLimit) * time.Second
+	}
+	if limit == 0 {
+		return nil
+	}
+	deadline := now.Add(limit)
+	return &deadline
+}
+
+// questionDeadline returns when the answer to the current question is due:
+// at the end of its time limit, in free rounds less the time it was already
+// shown, or at the round deadline when that comes first. Free rounds on the
+// round timer only have the latter. It returns false outside a running round.
+func questionDeadline(session *models.UserSession) (time.Time, bool) {
+	if !session.HasActiveGame || session.QuestionStartTime == nil {
+		return time.Time{}, false
+	}
+
+	var deadline time.Time
+	limit := models.QuestionTimeLimit * time.Second
+	switch {
+	case !freeNavigation(session):
+		deadline = session.QuestionStartTime.Add(limit)
+	case session.NavigationTimer == models.TimerQuestion:
+		spent := time.Duration(session.Spent[session.CurrentIndex]) * time.Millisecond
+		deadline = session.QuestionStartTime.Add(limit - spent)
+	}
+	if session.RoundDeadline != nil && (deadline.IsZero() || session.RoundDeadline.Before(deadline)) {
+		deadline = *session.RoundDeadline
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// overdue reports whether now is past deadline and the grace period that
+// covers the answer's way to the server. Every timer is enforced this way, so
+// an answer sent before an issued deadline is never late.
+func overdue(deadline, now time.Time) bool {
+	return now.After(deadline.Add(models.TimeGracePeriod * time.Second))
+}
+
+// secondsLeft returns the seconds from now to deadline, rounded up, and 0 once
+// it passed.
+func secondsLeft(deadline, now time.Time) int {
+	return max(int(math.Ceil(deadline.Sub(now).Seconds())), 0)
+}
+
+// issueDeadline stamps a question about to be sent with when its answer is
+// due and the seconds left until then.
+func issueDeadline(session *models.UserSession, q *models.QuestionDTO) {
+	if q == nil {
+		return
+	}
+	if deadline, ok := questionDeadline(session); ok {
+		q.Deadline = &deadline
+		q.TimeLimit = secondsLeft(deadline, session.Clock())
+	}
+}
+
+// roundExpired reports whether now is past the round deadline and its grace
+// period.
+func roundExpired(session *models.UserSession, now time.Time) bool {
+	return session.RoundDeadline != nil && session.HasActiveGame && overdue(*session.RoundDeadline, now)
+}
+
+// expireRound finalises a round whose deadline passed. A free round is scored
+// from its drafts; in a linear round every question not yet answered is
+// recorded as timed out.
+func (s *QuizService) expireRound(session *models.UserSession, now time.Time) {
+	if freeNavigation(session) {
+		s.finishOnTimeout(session, now)
+		return
+	}
+
+	addPlayTime(session, now.Sub(*session.QuestionStartTime))
+	session.Streak = 0
+	for idx := session.CurrentIndex; idx < len(session.Questions); idx++ {
+		session.IncorrectAnswers++
+		session.Answers = append(session.Answers, models.AnswerRecord{
+			Idx:        idx,
+			QuestionID: session.Questions[idx],
+			Answer:     -1,
+			Reason:     "timeout",
+		})
+		metrics.Answers.WithLabelValues("timeout").Inc()
+	}
+	session.CurrentIndex = len(session.Questions)
+	s.setCurrentTime(session)
+}
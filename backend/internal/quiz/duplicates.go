@@ -0,0 +1,97 @@
+package quiz
+
+import (
+	"math"
+	"quiz_backend/models"
+	"quiz_backend/pkg/similarity"
+)
+
+// maxDuplicateMatches caps how many similar questions are reported for one.
+const maxDuplicateMatches = 5
+
+// DuplicatePolicy configures near-duplicate detection on question create.
+type DuplicatePolicy struct {
+	// Threshold is the similarity score (0-1) from which questions count as
+	// near-duplicates.
+	Threshold float64
+	// Reject fails the create with 409 instead of returning a warning.
+	Reject bool
+}
+
+func (repo *QuizRepository) AllQuestions() ([]models.Question, error) {
+	var questions []models.Question
+	err := repo.Database.DB.Order("id").Find(&questions).Error
+	return questions, err
+}
+
+// FindDuplicates scores q against the whole question bank.
+func (repo *QuizRepository) FindDuplicates(q *models.Question, threshold float64) ([]models.DuplicateMatchDTO, error) {
+	questions, err := repo.AllQuestions()
+	if err != nil {
+		return nil, err
+	}
+	idx, byID := questionIndex(questions)
+	return toDuplicateMatches(idx.Similar(toDocument(q), threshold, maxDuplicateMatches), byID), nil
+}
+
+// DuplicateReport lists every pair of questions at or above threshold, each
+// pair once under the question with the lower ID.
+func (repo *QuizRepository) DuplicateReport(threshold float64) (models.DuplicateReportDTO, error) {
+	questions, err := repo.AllQuestions()
+	if err != nil {
+		return models.DuplicateReportDTO{}, err
+	}
+	idx, byID := questionIndex(questions)
+
+	report := models.DuplicateReportDTO{Threshold: threshold, Questions: []models.DuplicateReportEntryDTO{}}
+	for i := range questions {
+		q := &questions[i]
+		var later []similarity.Match
+		for _, m := range idx.Similar(toDocument(q), threshold, 0) {
+			if m.ID > q.ID {
+				later = append(later, m)
+			}
+		}
+		if len(later) == 0 {
+			continue
+		}
+		report.Questions = append(report.Questions, models.DuplicateReportEntryDTO{
+			ID:      q.ID,
+			Text:    q.Text,
+			Matches: toDuplicateMatches(later, byID),
+		})
+	}
+	return report, nil
+}
+
+func questionIndex(questions []models.Question) (*similarity.Index, map[uint]*models.Question) {
+	docs := make([]similarity.Document, len(questions))
+	byID := make(map[uint]*models.Question, len(questions))
+	for i := range questions {
+		docs[i] = toDocument(&questions[i])
+		byID[questions[i].ID] = &questions[i]
+	}
+	return similarity.NewIndex(docs), byID
+}
+
+func toDocument(q *models.Question) similarity.Document {
+	return similarity.Document{ID: q.ID, Text: q.Text, Options: q.Options}
+}
+
+func toDuplicateMatches(matches []similarity.Match, byID map[uint]*models.Question) []models.DuplicateMatchDTO {
+	out := make([]models.DuplicateMatchDTO, len(matches))
+	for i, m := range matches {
+		out[i] = models.DuplicateMatchDTO{
+			ID:          m.ID,
+			Text:        byID[m.ID].Text,
+			Score:       round3(m.Score),
+			TextScore:   round3(m.TextScore),
+			OptionScore: round3(m.OptionScore),
+		}
+	}
+	return out
+}
+
+func round3(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
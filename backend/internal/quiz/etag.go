@@ -0,0 +1,46 @@
+package quiz
+
+import (
+	"net/http"
+	"quiz_backend/models"
+	"quiz_backend/pkg/response"
+	"strconv"
+	"strings"
+)
+
+// questionETag is the strong entity tag of a question revision.
+func questionETag(q *models.Question) string {
+	return strconv.Quote(strconv.FormatUint(uint64(q.Version), 10))
+}
+
+func setQuestionETag(w http.ResponseWriter, q *models.Question) {
+	w.Header().Set("ETag", questionETag(q))
+}
+
+// matchesETag reports whether header (an If-Match or If-None-Match value)
+// lists the current ETag of q or is the "*" wildcard.
+func matchesETag(header string, q *models.Question) bool {
+	etag := questionETag(q)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch enforces optimistic locking on writes: the client must send
+// the ETag it last saw, and it must still be the current one.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, q *models.Question) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		response.PreconditionRequired(w, "If-Match header with the question ETag is required")
+		return false
+	}
+	if !matchesETag(ifMatch, q) {
+		response.PreconditionFailed(w, "Question was modified by someone else, reload and retry")
+		return false
+	}
+	return true
+}
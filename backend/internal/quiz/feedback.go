@@ -0,0 +1,115 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"quiz_backend/models"
+	"time"
+)
+
+// FeedbackPolicy decides when players learn whether their answers were
+// correct.
+type FeedbackPolicy string
+
+const (
+	// FeedbackImmediate reveals the result with every answer.
+	FeedbackImmediate FeedbackPolicy = "immediate"
+	// FeedbackEndOfRound reveals results once the round is over.
+	FeedbackEndOfRound FeedbackPolicy = "end_of_round"
+	// FeedbackNever never reveals results to players.
+	FeedbackNever FeedbackPolicy = "never"
+	// FeedbackAfterClose reveals results once the exam's close date passed.
+	FeedbackAfterClose FeedbackPolicy = "after_close_date"
+)
+
+// Feedback configures the feedback policy of new rounds. CloseAt is required
+// for FeedbackAfterClose.
+type Feedback struct {
+	Policy  FeedbackPolicy
+	CloseAt *time.Time
+}
+
+// ParseFeedback validates a policy name and, for after_close_date, its RFC
+// 3339 close date.
+func ParseFeedback(policy, closeAt string) (Feedback, error) {
+	f := Feedback{Policy: FeedbackPolicy(policy)}
+	switch f.Policy {
+	case FeedbackImmediate, FeedbackEndOfRound, FeedbackNever:
+		return f, nil
+	case FeedbackAfterClose:
+		if closeAt == "" {
+			return Feedback{}, errors.New("after_close_date policy needs a close date")
+		}
+		t, err := time.Parse(time.RFC3339, closeAt)
+		if err != nil {
+			return Feedback{}, fmt.Errorf("feedback close date %q: %w", closeAt, err)
+		}
+		f.CloseAt = &t
+		return f, nil
+	default:
+		return Feedback{}, fmt.Errorf("unknown feedback policy %q", policy)
+	}
+}
+
+// apply stamps the policy on a session starting a round, so a config change
+// never alters the rules of a round in progress.
+func (f Feedback) apply(session *models.UserSession) {
+	session.FeedbackPolicy = string(f.Policy)
+	session.FeedbackCloseAt = f.CloseAt
+}
+
+// sessionPolicy is the policy the session's round runs under. Rounds started
+// before policies existed behave as immediate.
+func sessionPolicy(session *models.UserSession) FeedbackPolicy {
+	if session.FeedbackPolicy == "" {
+		return FeedbackImmediate
+	}
+	return FeedbackPolicy(session.FeedbackPolicy)
+}
+
+// answerFeedback reports whether each answer response may reveal correctness.
+func answerFeedback(session *models.UserSession) bool {
+	if freeNavigation(session) && session.HasActiveGame {
+		// Answers may still change until the round is finished.
+		return false
+	}
+	return sessionPolicy(session) == FeedbackImmediate
+}
+
+// reviewOpen reports whether the results of the session's round may be shown
+// at now. When they may not, the reason says why.
+func reviewOpen(session *models.UserSession, now time.Time) (bool, string) {
+	switch sessionPolicy(session) {
+	case FeedbackImmediate:
+		return true, ""
+	case FeedbackEndOfRound:
+		if session.HasActiveGame {
+			return false, "Results are available once the round is finished"
+		}
+		return true, ""
+	case FeedbackAfterClose:
+		if session.FeedbackCloseAt == nil || now.Before(*session.FeedbackCloseAt) {
+			return false, "Results are available after " + closeAtString(session)
+		}
+		return true, ""
+	default:
+		return false, "Results of this quiz are not shown"
+	}
+}
+
+func closeAtString(session *models.UserSession) string {
+	if session.FeedbackCloseAt == nil {
+		return "the exam closes"
+	}
+	return session.FeedbackCloseAt.UTC().Format(time.RFC3339)
+}
+
+// ReviewLockedError is returned for a review the feedback policy does not
+// allow yet (or ever).
+type ReviewLockedError struct {
+	Reason string
+}
+
+func (e *ReviewLockedError) Error() string {
+	return "review locked: " + e.Reason
+}
@@ -1,25 +1,44 @@
 package quiz
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"quiz_backend/internal/media"
 	"quiz_backend/internal/session"
 	"quiz_backend/models"
+	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/mergepatch"
 	"quiz_backend/pkg/response"
+	"quiz_backend/pkg/validation"
 	"strconv"
-	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
 )
 
+const maxBodySize = 1 << 20
+
 type QuizHandlerDeps struct {
 	QuizRepository *QuizRepository
 	SessionService *session.Service
 	QuizService    *QuizService
+	Media          *media.MediaRepository
+	Duplicates     DuplicatePolicy
 }
 
 type QuizHandler struct {
 	repo        *QuizRepository
 	sess        *session.Service
 	quizService *QuizService
+	media       *media.MediaRepository
+	duplicates  DuplicatePolicy
 }
 
 func NewQuizHandler(mux *http.ServeMux, deps QuizHandlerDeps) {
@@ -27,56 +46,107 @@ func NewQuizHandler(mux *http.ServeMux, deps QuizHandlerDeps) {
 		repo:        deps.QuizRepository,
 		sess:        deps.SessionService,
 		quizService: deps.QuizService,
+		media:       deps.Media,
+		duplicates:  deps.Duplicates,
 	}
 
 	// Questions
 	mux.HandleFunc("GET /api/v1/questions", h.GetAllQuestions())
+	mux.HandleFunc("GET /api/v1/questions/duplicates", h.DuplicateReport())
 	mux.HandleFunc("GET /api/v1/questions/{id}", h.GetQuestion())
 	mux.HandleFunc("POST /api/v1/questions", h.CreateQuestion())
 	mux.HandleFunc("PUT /api/v1/questions/{id}", h.UpdateQuestion())
+	mux.HandleFunc("PATCH /api/v1/questions/{id}", h.PatchQuestion())
 	mux.HandleFunc("DELETE /api/v1/questions/{id}", h.DeleteQuestion())
 
+	// Translations
+	mux.HandleFunc("GET /api/v1/questions/{id}/translations", h.GetTranslations())
+	mux.HandleFunc("PUT /api/v1/questions/{id}/translations/{locale}", h.PutTranslation())
+	mux.HandleFunc("DELETE /api/v1/questions/{id}/translations/{locale}", h.DeleteTranslation())
+	mux.HandleFunc("GET /api/v1/translations/missing", h.MissingTranslations())
+
 	// Quiz
 	mux.HandleFunc("GET /api/v1/quiz/check-session", h.CheckSession())
 	mux.HandleFunc("GET /api/v1/quiz/start", h.StartQuiz())
 	mux.HandleFunc("POST /api/v1/quiz/answer", h.SubmitAnswer())
+	mux.HandleFunc("POST /api/v1/quiz/lifeline", h.UseLifeline())
+	mux.HandleFunc("GET /api/v1/quiz/review", h.Review())
+	mux.HandleFunc("POST /api/v1/quiz/navigate", h.Navigate())
+	mux.HandleFunc("POST /api/v1/quiz/flag", h.Flag())
+	mux.HandleFunc("POST /api/v1/quiz/finish", h.Finish())
+	mux.HandleFunc("POST /api/v1/quiz/pause", h.Pause())
+	mux.HandleFunc("POST /api/v1/quiz/resume", h.Resume())
+	mux.HandleFunc("GET /api/v1/time", h.ServerTime())
 }
 
 // GetAllQuestions godoc
-// @Summary      List questions with pagination and search
+// @Summary      List questions with pagination, sorting and filters
+// @Description  Pages by offset (page) or, when cursor is set, by keyset from next_cursor/prev_cursor of a previous response. A cursor carries its own sort and order.
+// @Description  search matches question text and options: words match as prefixes, "quoted phrases" exactly. Results are ranked by relevance unless another sort is given (relevance pages have no cursors) and carry highlighted fragments.
 // @Tags         questions
 // @Accept       json
 // @Produce      json
-// @Param        search  query  string  false  "Search in text"
-// @Param        page     query  int     false  "Page number"     default(1)
-// @Param        limit    query  int     false  "Items per page"  default(10)
+// @Param        search        query  string  false  "Full-text search in text and options"
+// @Param        option        query  string  false  "Search in option text"
+// @Param        created_from  query  string  false  "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param        created_to    query  string  false  "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param        updated_from  query  string  false  "Updated at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param        updated_to    query  string  false  "Updated at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param        sort          query  string  false  "Sort key (relevance is the default with search)"  Enums(relevance, id, created_at, updated_at, text) default(id)
+// @Param        order         query  string  false  "Sort direction"  Enums(asc, desc) default(asc)
+// @Param        cursor        query  string  false  "Page cursor"
+// @Param        page          query  int     false  "Page number"     default(1)
+// @Param        limit         query  int     false  "Items per page"  default(10)
 // @Success      200 {object} models.AdminPanelQuestionsDTO
+// @Failure      400 {object} response.Problem
 // @Router       /questions [get]
 func (h *QuizHandler) GetAllQuestions() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		search := strings.TrimSpace(r.URL.Query().Get("search"))
-		page := 1
-		limit := 10
+		query, errs := ParseQuestionQuery(r.URL.Query())
+		if errs != nil {
+			response.BadRequest(w, "Invalid query parameters", errs...)
+			return
+		}
 
-		if p := r.URL.Query().Get("page"); p != "" {
-			if n, err := strconv.Atoi(p); err == nil && n > 0 {
-				page = n
-			}
+		page, err := h.repo.GetQuestions(query)
+		if err != nil {
+			response.FromError(w, r, err, "Questions")
+			return
 		}
-		if l := r.URL.Query().Get("limit"); l != "" {
-			if n, err := strconv.Atoi(l); err == nil && n > 0 {
-				limit = n
+
+		dto := response.ToAdminPanelQuestionsDTO(page.Questions, page.Highlights, page.Total, page.Pages, page.Page, page.NextCursor, page.PrevCursor)
+		response.OK(w, dto)
+	}
+}
+
+// DuplicateReport godoc
+// @Summary      Report near-duplicate questions
+// @Description  Lists every pair of questions whose similarity (TF-IDF cosine of the text and overlap of the options) reaches threshold. Each pair appears once, under the question with the lower ID.
+// @Tags         questions
+// @Produce      json
+// @Param        threshold  query  number  false  "Minimum score between 0 and 1 (defaults to the server setting)"
+// @Success      200 {object} models.DuplicateReportDTO
+// @Failure      400 {object} response.Problem
+// @Router       /questions/duplicates [get]
+func (h *QuizHandler) DuplicateReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		threshold := h.duplicates.Threshold
+		if v := r.URL.Query().Get("threshold"); v != "" {
+			t, err := strconv.ParseFloat(v, 64)
+			if err != nil || t < 0 || t > 1 {
+				response.BadRequest(w, "Invalid query parameters",
+					response.FieldError{Field: "threshold", Code: "invalid", Message: "must be a number between 0 and 1"})
+				return
 			}
+			threshold = t
 		}
 
-		questions, total, pages, currentPage, err := h.repo.GetQuestions(search, page, limit)
+		report, err := h.repo.DuplicateReport(threshold)
 		if err != nil {
-			response.InternalError(w, "Failed to fetch questions")
+			response.FromError(w, r, err, "Questions")
 			return
 		}
-
-		dto := response.ToAdminPanelQuestionsDTO(questions, total, pages, currentPage)
-		response.OK(w, dto)
+		response.OK(w, report)
 	}
 }
 
@@ -85,10 +155,13 @@ func (h *QuizHandler) GetAllQuestions() http.HandlerFunc {
 // @Tags         questions
 // @Accept       json
 // @Produce      json
-// @Param        id   path   int   true   "Question ID"
+// @Param        id             path    int     true   "Question ID"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
 // @Success      200 {object} models.AdminPanelQuestionDTO
-// @Failure      400 {object} map[string]string
-// @Failure      404 {object} map[string]string
+// @Header       200 {string} ETag "Current question version"
+// @Success      304 "Not modified"
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
 // @Router       /questions/{id} [get]
 func (h *QuizHandler) GetQuestion() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -101,7 +174,13 @@ func (h *QuizHandler) GetQuestion() http.HandlerFunc {
 
 		q, err := h.repo.GetQuestionById(uint(id))
 		if err != nil {
-			response.InternalError(w, "Question not found")
+			response.FromError(w, r, err, "Question")
+			return
+		}
+
+		setQuestionETag(w, q)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && matchesETag(inm, q) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
@@ -112,12 +191,16 @@ func (h *QuizHandler) GetQuestion() http.HandlerFunc {
 
 // CreateQuestion godoc
 // @Summary      Create a new question
+// @Description  Existing questions scoring above the duplicate threshold are returned in possible_duplicates, or reject the create with 409 when the server runs with DUPLICATE_MODE=reject (unless force=true).
 // @Tags         questions
 // @Accept       json
 // @Produce      json
-// @Param        question  body  models.QuestionDataDTO  true  "Question data"
-// @Success      200 {object} models.AdminPanelQuestionDTO
-// @Failure      400 {object} map[string]string
+// @Param        question  body   models.QuestionDataDTO  true   "Question data"
+// @Param        force     query  bool                    false  "Create even if near-duplicates exist"
+// @Success      201 {object} models.AdminPanelQuestionDTO
+// @Failure      400 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Failure      422 {object} response.Problem
 // @Router       /questions [post]
 func (h *QuizHandler) CreateQuestion() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -127,45 +210,49 @@ func (h *QuizHandler) CreateQuestion() http.HandlerFunc {
 			return
 		}
 
-		if req.Text == "" {
-			response.BadRequest(w, "Text is required")
+		if !h.validateQuestion(w, r, req) {
 			return
 		}
-		if len(req.Options) < 2 {
-			response.BadRequest(w, "At least 2 options are required")
+
+		question := response.ToQuestion(req)
+
+		matches, err := h.repo.FindDuplicates(question, h.duplicates.Threshold)
+		if err != nil {
+			response.FromError(w, r, err, "Questions")
 			return
 		}
-		if req.CorrectAnswer < 0 || req.CorrectAnswer >= len(req.Options) {
-			response.BadRequest(w, "Correct answer index is invalid")
+		if len(matches) > 0 && h.duplicates.Reject && r.URL.Query().Get("force") != "true" {
+			response.DuplicateQuestion(w, "Question is too similar to existing ones, pass force=true to create it anyway", matches)
 			return
 		}
 
-		question := &models.Question{
-			Text:          req.Text,
-			Options:       req.Options,
-			CorrectAnswer: req.CorrectAnswer,
-		}
-
 		q, err := h.repo.CreateQuestion(question)
 		if err != nil {
-			response.InternalError(w, "Can't create question")
+			response.FromError(w, r, err, "Question")
 			return
 		}
+		setQuestionETag(w, q)
 		dto := response.ToAdminPanelQuestionDTO(q)
+		dto.PossibleDuplicates = matches
 		response.Created(w, dto)
 	}
 }
 
 // UpdateQuestion godoc
-// @Summary      Update question by ID
+// @Summary      Replace question by ID
+// @Description  Full replacement: text, options and correct_answer are all required.
 // @Tags         questions
 // @Accept       json
 // @Produce      json
-// @Param        id        path  int             true  "Question ID"
-// @Param        question  body  models.QuestionDataDTO  true  "Question data"
+// @Param        id        path    int                     true  "Question ID"
+// @Param        If-Match  header  string                  true  "ETag of the question being replaced"
+// @Param        question  body    models.QuestionDataDTO  true  "Question data"
 // @Success      200 {object} models.AdminPanelQuestionDTO
-// @Failure      400 {object} map[string]string
-// @Failure      404 {object} map[string]string
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      412 {object} response.Problem
+// @Failure      422 {object} response.Problem
+// @Failure      428 {object} response.Problem
 // @Router       /questions/{id} [put]
 func (h *QuizHandler) UpdateQuestion() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -176,34 +263,131 @@ func (h *QuizHandler) UpdateQuestion() http.HandlerFunc {
 			return
 		}
 
-		var req models.QuestionDataDTO
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		current, err := h.repo.GetQuestionById(uint(id))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		if !checkIfMatch(w, r, current) {
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
 			response.BadRequest(w, "Invalid JSON")
 			return
 		}
 
-		if req.Text == "" && req.Options == nil && req.CorrectAnswer < 0 {
-			response.BadRequest(w, "No data provided for update")
+		var req models.QuestionDataDTO
+		if err := decodeStrict(body, &req); err != nil {
+			response.BadRequest(w, "Invalid JSON: "+err.Error())
 			return
 		}
-		if len(req.Options) < 2 {
-			response.BadRequest(w, "Question must have at least 2 options")
+
+		if missing := missingFields(body, "text", "options", "correct_answer"); missing != nil {
+			response.UnprocessableEntity(w, "PUT replaces the whole question, all fields are required", missing...)
 			return
 		}
 
-		updateData := &models.Question{
-			Text:          req.Text,
-			Options:       req.Options,
-			CorrectAnswer: req.CorrectAnswer,
+		if !h.validateQuestion(w, r, req) {
+			return
 		}
 
-		q, err := h.repo.UpdateQuestion(uint(id), updateData)
+		q, err := h.repo.UpdateQuestion(uint(id), current.Version, response.ToQuestion(req))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		setQuestionETag(w, q)
+		dto := response.ToAdminPanelQuestionDTO(q)
+		response.OK(w, dto)
+	}
+}
+
+// PatchQuestion godoc
+// @Summary      Partially update question by ID
+// @Description  JSON Merge Patch (RFC 7386): only the members present in the body change, null removes a member. The merged question is validated as a whole.
+// @Tags         questions
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id        path    int                     true  "Question ID"
+// @Param        If-Match  header  string                  true  "ETag of the question being patched"
+// @Param        question  body    models.QuestionDataDTO  true  "Merge patch"
+// @Success      200 {object} models.AdminPanelQuestionDTO
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      415 {object} response.Problem
+// @Failure      412 {object} response.Problem
+// @Failure      422 {object} response.Problem
+// @Failure      428 {object} response.Problem
+// @Router       /questions/{id} [patch]
+func (h *QuizHandler) PatchQuestion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			response.BadRequest(w, "Invalid ID")
+			return
+		}
+
+		if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != mergepatch.ContentType && ct != "application/json" {
+			response.UnsupportedMediaType(w, "Use "+mergepatch.ContentType)
+			return
+		}
+
+		patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(patch, &members); err != nil {
+			response.BadRequest(w, "Merge patch must be a JSON object")
+			return
+		}
+		if len(members) == 0 {
+			response.BadRequest(w, "No data provided for update")
+			return
+		}
+
+		current, err := h.repo.GetQuestionById(uint(id))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		if !checkIfMatch(w, r, current) {
+			return
+		}
+
+		doc, err := json.Marshal(response.ToQuestionDataDTO(current))
 		if err != nil {
 			response.InternalError(w, "Can't update question")
 			return
 		}
+		merged, err := mergepatch.Apply(doc, patch)
+		if err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+
+		var req models.QuestionDataDTO
+		if err := decodeStrict(merged, &req); err != nil {
+			response.BadRequest(w, "Invalid merge patch: "+err.Error())
+			return
+		}
+
+		if !h.validateQuestion(w, r, req) {
+			return
+		}
+
+		q, err := h.repo.UpdateQuestion(uint(id), current.Version, response.ToQuestion(req))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		setQuestionETag(w, q)
 		dto := response.ToAdminPanelQuestionDTO(q)
-		response.Created(w, dto)
+		response.OK(w, dto)
 	}
 }
 
@@ -212,10 +396,13 @@ func (h *QuizHandler) UpdateQuestion() http.HandlerFunc {
 // @Tags         questions
 // @Accept       json
 // @Produce      json
-// @Param        id   path   int   true   "Question ID"
+// @Param        id        path    int     true  "Question ID"
+// @Param        If-Match  header  string  true  "ETag of the question being deleted"
 // @Success      200 {object} models.AdminPanelQuestionDTO
-// @Failure      400 {object} map[string]string
-// @Failure      404 {object} map[string]string
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      412 {object} response.Problem
+// @Failure      428 {object} response.Problem
 // @Router       /questions/{id} [delete]
 func (h *QuizHandler) DeleteQuestion() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -226,9 +413,18 @@ func (h *QuizHandler) DeleteQuestion() http.HandlerFunc {
 			return
 		}
 
-		q, err := h.repo.DeleteQuestion(uint(id))
+		current, err := h.repo.GetQuestionById(uint(id))
 		if err != nil {
-			response.InternalError(w, "Question not found")
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		if !checkIfMatch(w, r, current) {
+			return
+		}
+
+		q, err := h.repo.DeleteQuestion(uint(id), current.Version)
+		if err != nil {
+			response.FromError(w, r, err, "Question")
 			return
 		}
 
@@ -239,20 +435,22 @@ func (h *QuizHandler) DeleteQuestion() http.HandlerFunc {
 
 // CheckSession godoc
 // @Summary      Check if already have a session
+// @Description  Returns the session of the cookie, creating one if there is none. A finished round stays on the session, and reviewable, until the next round is started.
 // @Tags         quiz
 // @Produce      json
 // @Success      200 {object} models.SessionStats
-// @Failure      404 {object} map[string]string
+// @Failure      500 {object} response.Problem
 // @Router       /quiz/check-session [get]
 func (h *QuizHandler) CheckSession() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, err := h.sess.GetOrCreateSession(w, r)
 		if err != nil {
+			logger.FromContext(r.Context()).Error("get or create session", "err", err)
 			response.InternalError(w, "Failed to start session")
 			return
 		}
 
-		resp := h.quizService.GetSession(session)
+		resp := h.quizService.GetSession(r.Context(), session)
 
 		response.OK(w, resp)
 	}
@@ -260,9 +458,14 @@ func (h *QuizHandler) CheckSession() http.HandlerFunc {
 
 // StartQuiz godoc
 // @Summary      Start or resume quiz session
+// @Description  The question is returned in the session locale if one was chosen, else in the best match for Accept-Language, else in the default locale. While the round is paused no question is returned.
 // @Tags         quiz
 // @Produce      json
+// @Param        locale           query   string  false  "Remember this locale (BCP 47) for the session"
+// @Param        Accept-Language  header  string  false  "Preferred languages"
 // @Success      200 {object} models.StartResponse
+// @Failure      400 {object} response.Problem
+// @Failure      401 {object} response.Problem
 // @Router       /quiz/start [get]
 func (h *QuizHandler) StartQuiz() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -272,8 +475,20 @@ func (h *QuizHandler) StartQuiz() http.HandlerFunc {
 			return
 		}
 
-		resp, err := h.quizService.StartQuiz(session)
+		if v := r.URL.Query().Get("locale"); v != "" {
+			tag, err := language.Parse(v)
+			if err != nil {
+				response.BadRequest(w, "Invalid query parameters",
+					response.FieldError{Field: "locale", Code: "invalid", Message: "must be a BCP 47 language tag"})
+				return
+			}
+			session.Locale = tag.String()
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		resp, err := h.quizService.StartQuiz(r.Context(), session, acceptLanguages(r))
 		if err != nil {
+			logger.FromContext(r.Context()).Error("start quiz", "session_id", session.ID, "err", err)
 			response.InternalError(w, "Failed to start quiz")
 			return
 		}
@@ -283,13 +498,17 @@ func (h *QuizHandler) StartQuiz() http.HandlerFunc {
 
 // SubmitAnswer godoc
 // @Summary      Submit answer for current question
+// @Description  question_idx and question_id must identify the current question. Resubmitting an already answered question returns its original result. In free-navigation rounds the answer is saved as a draft, which may be changed until the round is finished, and the next open question is returned.
 // @Tags         quiz
 // @Accept       json
 // @Produce      json
-// @Param        body  body  models.AnswerRequest  true  "Answer data"
+// @Param        body             body    models.AnswerRequest  true   "Answer data"
+// @Param        Accept-Language  header  string                false  "Preferred languages for the next question"
 // @Success      200 {object} models.AnswerResponse
-// @Failure      400 {object} map[string]string
-// @Failure      401 {object} map[string]string
+// @Failure      400 {object} response.Problem
+// @Failure      401 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Failure      422 {object} response.Problem
 // @Router       /quiz/answer [post]
 func (h *QuizHandler) SubmitAnswer() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -299,23 +518,413 @@ func (h *QuizHandler) SubmitAnswer() http.HandlerFunc {
 			return
 		}
 
+		token, err := h.sess.Token(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		resp, err := h.quizService.SubmitAnswer(r.Context(), token, req, acceptLanguages(r))
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.Unauthorized(w, "No active session")
+			return
+		case errors.Is(err, ErrRoundFinished):
+			response.Conflict(w, "Quiz already completed")
+			return
+		case errors.Is(err, ErrRoundPaused):
+			response.Conflict(w, "Round is paused, resume it first")
+			return
+		case errors.Is(err, ErrAnswerMismatch):
+			response.Conflict(w, "Answer does not match the current question")
+			return
+		case errors.Is(err, ErrQuestionLocked):
+			response.Conflict(w, "Time for this question is up")
+			return
+		case errors.Is(err, ErrInvalidOption):
+			response.UnprocessableEntity(w, "Answer is invalid",
+				response.FieldError{Field: "answer", Code: "out_of_range", Message: "must be an option index or -1"})
+			return
+		case err != nil:
+			response.FromError(w, r, err, "Answer")
+			return
+		}
+
+		response.OK(w, resp)
+	}
+}
+
+// UseLifeline godoc
+// @Summary      Use a lifeline on the current question
+// @Description  hint returns the question's hint, fifty_fifty removes two wrong options (indexes in the player's order), skip moves on to the next question without points. Each kind is limited per round; using hint or fifty_fifty again on the same question repeats its effect for free. Hints and 50/50s take a share of the points off a correct answer. Once the question or the round has run out of time the timeout is recorded and the lifeline refused.
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param        body             body    models.LifelineRequest  true   "Lifeline and current question"
+// @Param        Accept-Language  header  string                  false  "Preferred languages for the next question"
+// @Success      200 {object} models.LifelineResponse
+// @Failure      400 {object} response.Problem
+// @Failure      401 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Failure      422 {object} response.Problem
+// @Router       /quiz/lifeline [post]
+func (h *QuizHandler) UseLifeline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.LifelineRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+		if errs := validation.Struct(req); errs != nil {
+			response.UnprocessableEntity(w, "Lifeline request is invalid", errs...)
+			return
+		}
+
+		token, err := h.sess.Token(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		resp, err := h.quizService.UseLifeline(r.Context(), token, req, acceptLanguages(r))
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.Unauthorized(w, "No active session")
+			return
+		case errors.Is(err, ErrRoundFinished):
+			response.Conflict(w, "Quiz already completed")
+			return
+		case errors.Is(err, ErrRoundPaused):
+			response.Conflict(w, "Round is paused, resume it first")
+			return
+		case errors.Is(err, ErrAnswerMismatch):
+			response.Conflict(w, "Lifeline does not match the current question")
+			return
+		case errors.Is(err, ErrQuestionLocked):
+			response.Conflict(w, "Time for this question is up")
+			return
+		case errors.Is(err, ErrLifelineExhausted):
+			response.Conflict(w, "No "+req.Lifeline+" lifelines left in this round")
+			return
+		case errors.Is(err, ErrLifelineUnavailable):
+			response.Conflict(w, "The "+req.Lifeline+" lifeline cannot be used on this question")
+			return
+		case err != nil:
+			response.FromError(w, r, err, "Lifeline")
+			return
+		}
+
+		response.OK(w, resp)
+	}
+}
+
+// Review godoc
+// @Summary      Review the latest round
+// @Description  Lists every answered question of the session's latest round with the submitted and the correct option (in the player's order). Available according to the round's feedback policy: immediate at any time, end_of_round once the round is finished, after_close_date once the exam closed, never not at all.
+// @Tags         quiz
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Preferred languages"
+// @Success      200 {object} models.ReviewDTO
+// @Failure      401 {object} response.Problem
+// @Failure      403 {object} response.Problem
+// @Router       /quiz/review [get]
+func (h *QuizHandler) Review() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := h.sess.GetSession(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		review, err := h.quizService.Review(r.Context(), session, acceptLanguages(r))
+		var locked *ReviewLockedError
+		switch {
+		case errors.As(err, &locked):
+			response.FeedbackLocked(w, locked.Reason)
+			return
+		case err != nil:
+			response.FromError(w, r, err, "Review")
+			return
+		}
+		response.OK(w, review)
+	}
+}
+
+// Navigate godoc
+// @Summary      Move to a question of a free-navigation round
+// @Description  Shows the question at question_idx. Only rounds in free navigation mode allow it, and under the question timer not once the question's time is up.
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param        body             body    models.NavigateRequest  true   "Question to show"
+// @Param        Accept-Language  header  string                  false  "Preferred languages"
+// @Success      200 {object} models.StartResponse
+// @Failure      400 {object} response.Problem
+// @Failure      401 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Router       /quiz/navigate [post]
+func (h *QuizHandler) Navigate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.NavigateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+
+		session, err := h.sess.GetSession(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		resp, err := h.quizService.Navigate(r.Context(), session, req.Idx, acceptLanguages(r))
+		if err != nil {
+			navigationError(w, r, err)
+			return
+		}
+		response.OK(w, resp)
+	}
+}
+
+// Flag godoc
+// @Summary      Flag a question of a free-navigation round
+// @Description  Marks the question at question_idx for review, or clears the mark when flagged is false.
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param        body  body  models.FlagRequest  true  "Question and flag"
+// @Success      200 {object} models.SessionStats
+// @Failure      400 {object} response.Problem
+// @Failure      401 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Router       /quiz/flag [post]
+func (h *QuizHandler) Flag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.FlagRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+
+		session, err := h.sess.GetSession(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		stats, err := h.quizService.Flag(r.Context(), session, req)
+		if err != nil {
+			navigationError(w, r, err)
+			return
+		}
+		response.OK(w, stats)
+	}
+}
+
+// Finish godoc
+// @Summary      Submit a free-navigation round
+// @Description  Scores the drafts of every question and ends the round. Unanswered questions count as skipped. Results are shown according to the round's feedback policy.
+// @Tags         quiz
+// @Produce      json
+// @Success      200 {object} models.SessionStats
+// @Failure      401 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Router       /quiz/finish [post]
+func (h *QuizHandler) Finish() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		session, err := h.sess.GetSession(r)
 		if err != nil {
 			response.Unauthorized(w, "No active session")
 			return
 		}
 
-		if !session.HasActiveGame {
-			response.BadRequest(w, "Quiz already completed")
+		stats, err := h.quizService.Finish(r.Context(), session)
+		if err != nil {
+			navigationError(w, r, err)
 			return
 		}
+		response.OK(w, stats)
+	}
+}
 
-		resp, err := h.quizService.ProcessAnswer(session, req.Answer)
+// Pause godoc
+// @Summary      Pause the running round
+// @Description  Stops the question and round timers until the round is resumed; the current question is withheld meanwhile. How often a round may be paused is configured per quiz. Pausing a paused round changes nothing.
+// @Tags         quiz
+// @Produce      json
+// @Success      200 {object} models.StartResponse
+// @Failure      401 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Router       /quiz/pause [post]
+func (h *QuizHandler) Pause() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := h.sess.GetSession(r)
+		if err != nil {
+			response.Unauthorized(w, "No active session")
+			return
+		}
+
+		resp, err := h.quizService.Pause(r.Context(), session)
+		switch {
+		case errors.Is(err, ErrRoundFinished):
+			response.Conflict(w, "Quiz already completed")
+			return
+		case errors.Is(err, ErrPausingDisabled):
+			response.Conflict(w, "This quiz does not allow pausing")
+			return
+		case errors.Is(err, ErrPauseLimit):
+			response.Conflict(w, "No pauses left in this round")
+			return
+		case errors.Is(err, db.ErrVersionConflict):
+			response.Conflict(w, "Round was changed by another request, reload and retry")
+			return
+		case err != nil:
+			response.FromError(w, r, err, "Round")
+			return
+		}
+		response.OK(w, resp)
+	}
+}
+
+// Resume godoc
+// @Summary      Resume a paused round
+// @Description  Restarts the timers where they stopped and returns the current question as GET /quiz/start does. Resuming a running round just returns it.
+// @Tags         quiz
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Preferred languages"
+// @Success      200 {object} models.StartResponse
+// @Failure      401 {object} response.Problem
+// @Failure      409 {object} response.Problem
+// @Router       /quiz/resume [post]
+func (h *QuizHandler) Resume() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := h.sess.GetSession(r)
 		if err != nil {
-			response.InternalError(w, "Failed to process answer")
+			response.Unauthorized(w, "No active session")
 			return
 		}
 
+		w.Header().Add("Vary", "Accept-Language")
+		resp, err := h.quizService.Resume(r.Context(), session, acceptLanguages(r))
+		switch {
+		case errors.Is(err, ErrRoundFinished):
+			response.Conflict(w, "Quiz already completed")
+			return
+		case errors.Is(err, db.ErrVersionConflict):
+			response.Conflict(w, "Round was changed by another request, reload and retry")
+			return
+		case err != nil:
+			response.FromError(w, r, err, "Round")
+			return
+		}
 		response.OK(w, resp)
 	}
 }
+
+// ServerTime godoc
+// @Summary      Server clock
+// @Description  Returns the current server time so clients can estimate the offset of their clock, e.g. as server_time minus the midpoint of the request's round trip, and count down to question deadlines accurately.
+// @Tags         quiz
+// @Produce      json
+// @Success      200 {object} models.ServerTimeDTO
+// @Router       /time [get]
+func (h *QuizHandler) ServerTime() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		w.Header().Set("Cache-Control", "no-store")
+		response.OK(w, models.ServerTimeDTO{ServerTime: now, UnixMs: now.UnixMilli()})
+	}
+}
+
+// navigationError writes the problem for an error of the free-navigation
+// endpoints.
+func navigationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrRoundFinished):
+		response.Conflict(w, "Quiz already completed")
+	case errors.Is(err, ErrRoundPaused):
+		response.Conflict(w, "Round is paused, resume it first")
+	case errors.Is(err, ErrLinearNavigation):
+		response.Conflict(w, "This round does not allow free navigation")
+	case errors.Is(err, ErrQuestionIndex):
+		response.NotFound(w, "No question at this index")
+	case errors.Is(err, ErrQuestionLocked):
+		response.Conflict(w, "Time for this question is up")
+	case errors.Is(err, db.ErrVersionConflict):
+		response.Conflict(w, "Round was changed by another request, reload and retry")
+	default:
+		response.FromError(w, r, err, "Round")
+	}
+}
+
+// decodeStrict unmarshals a JSON object into dst rejecting unknown members.
+func decodeStrict(body []byte, dst any) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
+
+// missingFields reports the required top-level members absent from body.
+func missingFields(body []byte, required ...string) []response.FieldError {
+	var members map[string]json.RawMessage
+	_ = json.Unmarshal(body, &members)
+
+	var missing []response.FieldError
+	for _, name := range required {
+		if _, ok := members[name]; !ok {
+			missing = append(missing, response.FieldError{Field: name, Code: "required", Message: "is required"})
+		}
+	}
+	return missing
+}
+
+// validateQuestion checks req against its validation rules and verifies that
+// the media it references was uploaded. It writes a 422 and returns false
+// when anything is wrong.
+func (h *QuizHandler) validateQuestion(w http.ResponseWriter, r *http.Request, req models.QuestionDataDTO) bool {
+	if errs := validation.Struct(req); errs != nil {
+		response.UnprocessableEntity(w, "Question is invalid", errs...)
+		return false
+	}
+
+	missing, err := h.media.MissingKeys(append([]string{req.Media}, req.OptionMedia...))
+	if err != nil {
+		response.FromError(w, r, err, "Media")
+		return false
+	}
+	if len(missing) == 0 {
+		return true
+	}
+
+	unknown := make(map[string]bool, len(missing))
+	for _, k := range missing {
+		unknown[k] = true
+	}
+	var errs []response.FieldError
+	if unknown[req.Media] {
+		errs = append(errs, response.FieldError{Field: "media", Code: "unknown_media", Message: "refers to media that was not uploaded"})
+	}
+	for i, k := range req.OptionMedia {
+		if unknown[k] {
+			errs = append(errs, response.FieldError{Field: fmt.Sprintf("option_media[%d]", i), Code: "unknown_media", Message: "refers to media that was not uploaded"})
+		}
+	}
+	response.UnprocessableEntity(w, "Question is invalid", errs...)
+	return false
+}
+
+// acceptLanguages returns the Accept-Language preferences of r, best first.
+// A malformed header counts as no preference.
+func acceptLanguages(r *http.Request) []language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+	return tags
+}
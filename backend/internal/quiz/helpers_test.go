@@ -0,0 +1,139 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"quiz_backend/internal/media"
+	"quiz_backend/internal/session"
+	"quiz_backend/models"
+	"quiz_backend/pkg/db"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestRepo returns a repository on a fresh database holding n questions
+// whose first option is the correct one.
+func newTestRepo(t *testing.T, n int) *QuizRepository {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "quiz.db")), &gorm.Config{
+		TranslateError: true,
+		Logger:         gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	database := &db.Db{DB: conn}
+	if err := database.AutoMigrate(db.Tables...); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewQuizRepository(database)
+	for i := range n {
+		q := &models.Question{
+			Text:    fmt.Sprintf("Question %d?", i+1),
+			Options: []string{"right", "wrong 1", "wrong 2", "wrong 3"},
+			Hint:    "Not the wrong ones.",
+		}
+		if _, err := repo.CreateQuestion(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// testSettings are linear rounds with immediate feedback and flat scoring;
+// tests adjust what they exercise.
+func testSettings(t *testing.T) Settings {
+	t.Helper()
+	locales, err := ParseLocales("en", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feedback, err := ParseFeedback(string(FeedbackImmediate), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoring, err := NewScoringPolicy("flat", testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	navigation, err := ParseNavigation(models.NavigationLinear, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Settings{
+		Locales:       locales,
+		Feedback:      feedback,
+		Scoring:       scoring,
+		ScoringParams: testParams,
+		Lifelines:     Lifelines{Hints: 1, FiftyFifty: 1, Skips: 1},
+		Navigation:    navigation,
+	}
+}
+
+// testPlayer plays through the quiz API with one session cookie.
+type testPlayer struct {
+	t       *testing.T
+	handler http.Handler
+	cookie  *http.Cookie
+}
+
+func newTestPlayer(t *testing.T, repo *QuizRepository, settings Settings) *testPlayer {
+	t.Helper()
+	mux := http.NewServeMux()
+	NewQuizHandler(mux, QuizHandlerDeps{
+		QuizRepository: repo,
+		SessionService: session.NewService(repo),
+		QuizService:    NewQuizService(repo, settings),
+		Media:          media.NewMediaRepository(repo.Database),
+	})
+	return &testPlayer{t: t, handler: mux}
+}
+
+// call sends body, if any, as JSON and decodes the response into out, if
+// given. It returns the status code.
+func (p *testPlayer) call(method, path string, body, out any) int {
+	p.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			p.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, "/api/v1"+path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if p.cookie != nil {
+		req.AddCookie(p.cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	p.handler.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == session.CookieName {
+			p.cookie = c
+		}
+	}
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			p.t.Fatalf("%s %s: decode %s: %v", method, path, rec.Body, err)
+		}
+	}
+	return rec.Code
+}
+
+// session loads the player's session as stored.
+func (p *testPlayer) session(repo *QuizRepository) *models.UserSession {
+	p.t.Helper()
+	s, err := repo.GetSessionByToken(p.cookie.Value)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	mrand "math/rand"
+	"quiz_backend/models"
+	"quiz_backend/pkg/content"
+	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/response"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrLifelineExhausted   = errors.New("no lifelines of this kind left in the round")
+	ErrLifelineUnavailable = errors.New("lifeline cannot be used on this question")
+)
+
+// Lifelines sets how many lifelines of each kind a round allows and which
+// share of a correct answer's points hints and 50/50s cost.
+type Lifelines struct {
+	Hints             int
+	FiftyFifty        int
+	Skips             int
+	HintPenalty       float64
+	FiftyFiftyPenalty float64
+}
+
+func (l Lifelines) limit(kind string) int {
+	switch kind {
+	case models.LifelineHint:
+		return l.Hints
+	case models.LifelineFiftyFifty:
+		return l.FiftyFifty
+	case models.LifelineSkip:
+		return l.Skips
+	default:
+		return 0
+	}
+}
+
+// left counts the lifelines of every kind the session's round still allows.
+func (l Lifelines) left(session *models.UserSession) map[string]int {
+	left := map[string]int{
+		models.LifelineHint:       l.Hints,
+		models.LifelineFiftyFifty: l.FiftyFifty,
+		models.LifelineSkip:       l.Skips,
+	}
+	for _, rec := range session.Lifelines {
+		left[rec.Kind] = max(left[rec.Kind]-1, 0)
+	}
+	return left
+}
+
+// deduction is the share of credit the lifelines used on the question at idx
+// cost.
+func (l Lifelines) deduction(session *models.UserSession, idx int) float64 {
+	var d float64
+	for _, rec := range session.Lifelines {
+		if rec.Idx != idx {
+			continue
+		}
+		switch rec.Kind {
+		case models.LifelineHint:
+			d += l.HintPenalty
+		case models.LifelineFiftyFifty:
+			d += l.FiftyFiftyPenalty
+		}
+	}
+	return min(d, 1)
+}
+
+// UseLifeline applies a lifeline to the current question of the session
+// identified by token. Like SubmitAnswer it runs in a transaction with a
+// version check. A hint or 50/50 used again on the same question is replayed
+// without using up another one. Once the question's or the round's time is up
+// no lifeline helps any more: the timeout is recorded instead.
+func (s *QuizService) UseLifeline(ctx context.Context, token string, req models.LifelineRequest, accept []language.Tag) (models.LifelineResponse, error) {
+	var resp models.LifelineResponse
+	var expired, err error
+	for attempt := 0; attempt < maxSubmitAttempts; attempt++ {
+		expired = nil
+		err = s.repo.Transaction(func(tx *QuizRepository) error {
+			session, err := tx.GetSessionByToken(token)
+			if err != nil {
+				return err
+			}
+			if !session.HasActiveGame {
+				return ErrRoundFinished
+			}
+			if session.PausedAt != nil {
+				return ErrRoundPaused
+			}
+			// The timeout must be stored, so it is reported once the
+			// transaction is committed.
+			if timedOut, _, _ := s.handleTimeout(session); timedOut {
+				expired = ErrQuestionLocked
+				if !session.HasActiveGame {
+					expired = ErrRoundFinished
+				}
+				return tx.UpdateSession(session)
+			}
+			if req.Idx != session.CurrentIndex || req.Id < 0 || uint(req.Id) != session.Questions[session.CurrentIndex] {
+				return ErrAnswerMismatch
+			}
+
+			resp, err = s.applyLifeline(ctx, tx, session, req.Lifeline, localePrefs(session, accept))
+			if err != nil {
+				return err
+			}
+			return tx.UpdateSession(session)
+		})
+		if !errors.Is(err, db.ErrVersionConflict) {
+			break
+		}
+		logger.FromContext(ctx).Debug("session changed concurrently, retrying lifeline", "attempt", attempt+1)
+	}
+	if err == nil && expired != nil {
+		return models.LifelineResponse{}, expired
+	}
+	return resp, err
+}
+
+func (s *QuizService) applyLifeline(ctx context.Context, repo *QuizRepository, session *models.UserSession, kind string, prefs []language.Tag) (models.LifelineResponse, error) {
+	idx := session.CurrentIndex
+	q, err := repo.GetQuestionById(session.Questions[idx])
+	if err != nil {
+		return models.LifelineResponse{}, err
+	}
+	// The hint comes in the language the question is shown in.
+	q = localize(repo, s.settings.Locales, q, prefs)
+	order := optionOrder(session, idx, len(q.Options))
+
+	rec := findLifeline(session, idx, kind)
+	if rec == nil {
+		if s.settings.Lifelines.left(session)[kind] == 0 {
+			return models.LifelineResponse{}, ErrLifelineExhausted
+		}
+		created := models.LifelineRecord{Idx: idx, QuestionID: q.ID, Kind: kind}
+		switch kind {
+		case models.LifelineSkip:
+			// Free navigation already lets the player move on.
+			if freeNavigation(session) {
+				return models.LifelineResponse{}, ErrLifelineUnavailable
+			}
+		case models.LifelineHint:
+			if q.Hint == "" {
+				return models.LifelineResponse{}, ErrLifelineUnavailable
+			}
+		case models.LifelineFiftyFifty:
+			if created.Removed = removeWrongOptions(q, 2); created.Removed == nil {
+				return models.LifelineResponse{}, ErrLifelineUnavailable
+			}
+		}
+		session.Lifelines = append(session.Lifelines, created)
+		rec = &session.Lifelines[len(session.Lifelines)-1]
+	}
+
+	resp := models.LifelineResponse{Lifeline: kind}
+	switch kind {
+	case models.LifelineHint:
+		resp.HintHTML = content.Render(content.FormatMarkdown, q.Hint)
+	case models.LifelineFiftyFifty:
+		for _, stored := range rec.Removed {
+			resp.RemovedOptions = append(resp.RemovedOptions, shownIndex(order, stored))
+		}
+		slices.Sort(resp.RemovedOptions)
+	case models.LifelineSkip:
+		s.skipQuestion(session, q)
+		resp.NextQuestion = questionDTO(s.currentQuestion(ctx, repo, session, prefs))
+		issueDeadline(session, resp.NextQuestion)
+	}
+	resp.LifelinesLeft = s.settings.Lifelines.left(session)
+	resp.SessionStats = response.ToCheckResponse(session, answerFeedback(session))
+	return resp, nil
+}
+
+// skipQuestion records the current question as skipped, worth no points, and
+// moves on. It ends the streak.
+func (s *QuizService) skipQuestion(session *models.UserSession, q *models.Question) {
+	session.Streak = 0
+	addPlayTime(session, session.Clock().Sub(*session.QuestionStartTime))
+	session.Answers = append(session.Answers, models.AnswerRecord{
+		Idx:        session.CurrentIndex,
+		QuestionID: q.ID,
+		Answer:     -1,
+		Reason:     "skipped",
+	})
+	session.CurrentIndex++
+	s.setCurrentTime(session)
+}
+
+// findLifeline returns the lifeline of kind used on the question at idx.
+func findLifeline(session *models.UserSession, idx int, kind string) *models.LifelineRecord {
+	for i := range session.Lifelines {
+		rec := &session.Lifelines[i]
+		if rec.Idx == idx && rec.Kind == kind {
+			return rec
+		}
+	}
+	return nil
+}
+
+// removeWrongOptions picks up to n wrong options at random, always leaving
+// one. It returns nil when there is nothing to remove.
+func removeWrongOptions(q *models.Question, n int) []int {
+	var wrong []int
+	for i := range q.Options {
+		if i != q.CorrectAnswer {
+			wrong = append(wrong, i)
+		}
+	}
+	n = min(n, len(wrong)-1)
+	if n <= 0 {
+		return nil
+	}
+	mrand.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+	removed := wrong[:n]
+	slices.Sort(removed)
+	return removed
+}
+
+func questionDTO(q *models.Question) *models.QuestionDTO {
+	if q == nil {
+		return nil
+	}
+	dto := response.ToQuestionDTO(q)
+	return &dto
+}
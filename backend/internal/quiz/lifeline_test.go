@@ -0,0 +1,84 @@
+package quiz
+
+import (
+	"net/http"
+	"quiz_backend/models"
+	"testing"
+	"time"
+)
+
+func TestLifelineAfterTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		lifeline string
+		expire   func(s *models.UserSession)
+		want     int
+		answers  int
+		active   bool
+	}{
+		{"skip after the question timed out", models.LifelineSkip,
+			func(s *models.UserSession) { s.QuestionStartTime = ago(40 * time.Second) }, http.StatusConflict, 1, true},
+		{"hint after the question timed out", models.LifelineHint,
+			func(s *models.UserSession) { s.QuestionStartTime = ago(40 * time.Second) }, http.StatusConflict, 1, true},
+		{"skip after the round deadline", models.LifelineSkip,
+			func(s *models.UserSession) { s.RoundDeadline = ago(10 * time.Second) }, http.StatusConflict, 3, false},
+		{"fifty_fifty after the round deadline", models.LifelineFiftyFifty,
+			func(s *models.UserSession) { s.RoundDeadline = ago(10 * time.Second) }, http.StatusConflict, 3, false},
+		{"skip in time", models.LifelineSkip,
+			func(s *models.UserSession) {}, http.StatusOK, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t, 3)
+			settings := testSettings(t)
+			settings.RoundTimeLimit = time.Minute
+			p := newTestPlayer(t, repo, settings)
+
+			p.call(http.MethodGet, "/quiz/check-session", nil, nil)
+			var start models.StartResponse
+			if code := p.call(http.MethodGet, "/quiz/start", nil, &start); code != http.StatusOK {
+				t.Fatalf("start: status %d", code)
+			}
+			s := p.session(repo)
+			tt.expire(s)
+			if err := repo.UpdateSession(s); err != nil {
+				t.Fatal(err)
+			}
+
+			var resp models.LifelineResponse
+			req := models.LifelineRequest{Lifeline: tt.lifeline, Idx: 0, Id: int(start.NextQuestion.ID)}
+			if code := p.call(http.MethodPost, "/quiz/lifeline", req, &resp); code != tt.want {
+				t.Fatalf("lifeline: status %d, want %d", code, tt.want)
+			}
+			if tt.want != http.StatusOK && resp.HintHTML != "" {
+				t.Errorf("hint revealed after the time was up: %q", resp.HintHTML)
+			}
+
+			s = p.session(repo)
+			if len(s.Answers) != tt.answers || s.HasActiveGame != tt.active {
+				t.Fatalf("session has %d answers, active %t; want %d, active %t", len(s.Answers), s.HasActiveGame, tt.answers, tt.active)
+			}
+			want := "timeout"
+			if tt.want == http.StatusOK {
+				want = "skipped"
+			}
+			for _, rec := range s.Answers {
+				if rec.Reason != want {
+					t.Errorf("question %d recorded as %q, want %q", rec.Idx, rec.Reason, want)
+				}
+			}
+			if tt.active && tt.want != http.StatusOK && s.IncorrectAnswers != 1 {
+				t.Errorf("incorrect answers = %d, want the timeout counted", s.IncorrectAnswers)
+			}
+			if len(s.Lifelines) != 0 && tt.want != http.StatusOK {
+				t.Errorf("lifeline recorded after the time was up: %+v", s.Lifelines)
+			}
+		})
+	}
+}
+
+func ago(d time.Duration) *time.Time {
+	t := time.Now().Add(-d)
+	return &t
+}
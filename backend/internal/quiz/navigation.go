@@ -0,0 +1,322 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"quiz_backend/models"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/metrics"
+	"quiz_backend/pkg/response"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrLinearNavigation = errors.New("round does not allow free navigation")
+	ErrQuestionIndex    = errors.New("no question at this index")
+	ErrQuestionLocked   = errors.New("time for this question is up")
+	ErrInvalidOption    = errors.New("answer is not an option of the question")
+)
+
+// Navigation configures how new rounds move between questions. Linear rounds
+// always use the question timer.
+type Navigation struct {
+	Mode  string
+	Timer string
+}
+
+// ParseNavigation validates a navigation mode and, for free navigation, its
+// timer.
+func ParseNavigation(mode, timer string) (Navigation, error) {
+	switch mode {
+	case models.NavigationLinear:
+		return Navigation{Mode: mode, Timer: models.TimerQuestion}, nil
+	case models.NavigationFree:
+		if timer != models.TimerQuestion && timer != models.TimerRound {
+			return Navigation{}, fmt.Errorf("unknown navigation timer %q", timer)
+		}
+		return Navigation{Mode: mode, Timer: timer}, nil
+	default:
+		return Navigation{}, fmt.Errorf("unknown navigation mode %q", mode)
+	}
+}
+
+// apply stamps the navigation on a session starting a round and, for free
+// navigation, prepares empty drafts.
+func (n Navigation) apply(session *models.UserSession) {
+	session.NavigationMode = n.Mode
+	session.NavigationTimer = n.Timer
+	session.Drafts, session.Flags, session.Spent = nil, nil, nil
+	if n.Mode != models.NavigationFree {
+		return
+	}
+
+	count := len(session.Questions)
+	session.Drafts = make([]int, count)
+	for i := range session.Drafts {
+		session.Drafts[i] = -1
+	}
+	session.Flags = make([]bool, count)
+	session.Spent = make([]int, count)
+}
+
+func freeNavigation(session *models.UserSession) bool {
+	return session.NavigationMode == models.NavigationFree
+}
+
+// Navigate moves a free-navigation round to the question at idx.
+func (s *QuizService) Navigate(ctx context.Context, session *models.UserSession, idx int, accept []language.Tag) (models.StartResponse, error) {
+	if err := s.checkFreeRound(session); err != nil {
+		return models.StartResponse{}, err
+	}
+	if idx < 0 || idx >= len(session.Questions) {
+		return models.StartResponse{}, ErrQuestionIndex
+	}
+	if questionLocked(session, idx) {
+		return models.StartResponse{}, ErrQuestionLocked
+	}
+
+	commitView(session, time.Now())
+	session.CurrentIndex = idx
+	_, timeLimit, _ := s.handleTimeout(session)
+	nextQ := s.currentQuestion(ctx, s.repo, session, localePrefs(session, accept))
+
+	if err := s.repo.UpdateSession(session); err != nil {
+		return models.StartResponse{}, err
+	}
+	resp := response.ToStartResponse(timeLimit, answerFeedback(session), session, nextQ)
+	issueDeadline(session, resp.NextQuestion)
+	resp.LifelinesLeft = s.settings.Lifelines.left(session)
+	return resp, nil
+}
+
+// Flag marks the question at idx of a free-navigation round for review, or
+// clears the mark.
+func (s *QuizService) Flag(ctx context.Context, session *models.UserSession, req models.FlagRequest) (models.SessionStats, error) {
+	if err := s.checkFreeRound(session); err != nil {
+		return models.SessionStats{}, err
+	}
+	if req.Idx < 0 || req.Idx >= len(session.Flags) {
+		return models.SessionStats{}, ErrQuestionIndex
+	}
+
+	session.Flags[req.Idx] = req.Flagged
+	if err := s.repo.UpdateSession(session); err != nil {
+		return models.SessionStats{}, err
+	}
+	return response.ToCheckResponse(session, answerFeedback(session)), nil
+}
+
+// Finish submits the drafts of a free-navigation round: they are scored in
+// question order and the round ends. Unanswered questions count as skipped,
+// or as timed out when their time ran out.
+// Should a draft have been saved meanwhile, the version check fails the
+// finish with db.ErrVersionConflict.
+func (s *QuizService) Finish(ctx context.Context, session *models.UserSession) (models.SessionStats, error) {
+	if err := s.checkFreeRound(session); err != nil {
+		return models.SessionStats{}, err
+	}
+	if err := s.finishFree(s.repo, session, time.Now(), "skipped"); err != nil {
+		return models.SessionStats{}, err
+	}
+	if err := s.repo.UpdateSession(session); err != nil {
+		return models.SessionStats{}, err
+	}
+	return response.ToCheckResponse(session, answerFeedback(session)), nil
+}
+
+// checkFreeRound applies timeouts and fails unless the session is in an
+// active, running free-navigation round.
+func (s *QuizService) checkFreeRound(session *models.UserSession) error {
+	if !session.HasActiveGame {
+		return ErrRoundFinished
+	}
+	if !freeNavigation(session) {
+		return ErrLinearNavigation
+	}
+	if session.PausedAt != nil {
+		return ErrRoundPaused
+	}
+	s.handleTimeout(session)
+	if !session.HasActiveGame {
+		if err := s.repo.UpdateSession(session); err != nil {
+			return err
+		}
+		return ErrRoundFinished
+	}
+	return nil
+}
+
+// saveDraft records the answer to the current question of a free-navigation
+// round and moves on to the next open question, if there is one after it.
+// The result stays hidden until the round is finished.
+func (s *QuizService) saveDraft(ctx context.Context, repo *QuizRepository, session *models.UserSession, answer int, prefs []language.Tag) (models.AnswerResponse, error) {
+	idx := session.CurrentIndex
+	q, err := repo.GetQuestionById(session.Questions[idx])
+	if err != nil {
+		return models.AnswerResponse{}, err
+	}
+	if answer < -1 || answer >= len(q.Options) {
+		return models.AnswerResponse{}, ErrInvalidOption
+	}
+	if timedOut, _, _ := s.handleTimeout(session); timedOut {
+		if !session.HasActiveGame {
+			return models.AnswerResponse{}, ErrRoundFinished
+		}
+		return models.AnswerResponse{}, ErrQuestionLocked
+	}
+
+	session.Drafts[idx] = answer
+	commitView(session, time.Now())
+	for next := idx + 1; next < len(session.Questions); next++ {
+		if !questionLocked(session, next) {
+			session.CurrentIndex = next
+			break
+		}
+	}
+
+	resp := models.AnswerResponse{
+		Reason:           "recorded",
+		FeedbackWithheld: true,
+		SessionStats:     response.ToCheckResponse(session, answerFeedback(session)),
+		NextQuestion:     questionDTO(s.currentQuestion(ctx, repo, session, prefs)),
+	}
+	issueDeadline(session, resp.NextQuestion)
+	return resp, nil
+}
+
+// handleFreeTimeout enforces the question timer of a free-navigation round:
+// only the question on screen uses up its time; when it runs out its draft
+// becomes final and the next open question is shown. Under the round timer
+// only the round deadline, which handleTimeout enforces, applies. It returns
+// what handleTimeout does, bar the time limit handleTimeout works out.
+func (s *QuizService) handleFreeTimeout(session *models.UserSession, now time.Time) (timedOut bool, timeLimit int, elapsed time.Duration) {
+	timeLimit = models.QuestionTimeLimit
+	if session.QuestionStartTime == nil || !session.HasActiveGame {
+		return
+	}
+
+	idx := session.CurrentIndex
+	spent := time.Duration(session.Spent[idx]) * time.Millisecond
+	elapsed = spent + now.Sub(*session.QuestionStartTime)
+	if session.NavigationTimer == models.TimerRound ||
+		!overdue(session.QuestionStartTime.Add(models.QuestionTimeLimit*time.Second-spent), now) {
+		return
+	}
+
+	commitView(session, now)
+	metrics.Answers.WithLabelValues("timeout").Inc()
+	if next := nextOpenQuestion(session, idx); next >= 0 {
+		session.CurrentIndex = next
+	} else {
+		s.finishOnTimeout(session, now)
+	}
+	return true, timeLimit, elapsed
+}
+
+// finishOnTimeout finishes a round whose time ran out. Scoring needs the
+// questions; should they fail to load the round still ends, unscored.
+func (s *QuizService) finishOnTimeout(session *models.UserSession, now time.Time) {
+	if err := s.finishFree(s.repo, session, now, "timeout"); err != nil {
+		logger.FromContext(context.Background()).Error("score timed out round", "session_id", session.ID, "err", err)
+		session.CurrentIndex = len(session.Questions)
+		s.setCurrentTime(session)
+	}
+}
+
+// finishFree turns the drafts into answer records, scoring them in question
+// order, and ends the round. Unanswered questions get reason unanswered, or
+// timeout when their time ran out.
+func (s *QuizService) finishFree(repo *QuizRepository, session *models.UserSession, now time.Time, unanswered string) error {
+	commitView(session, now)
+	questions, err := repo.GetQuestionsByIds(session.Questions)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+
+	policy := s.scoringFor(session)
+	session.Answers = nil
+	session.Score, session.Streak = 0, 0
+	spent := 0
+	for idx, id := range session.Questions {
+		spent += session.Spent[idx]
+		rec := models.AnswerRecord{Idx: idx, QuestionID: id, Answer: session.Drafts[idx], Reason: unanswered}
+		if questionLocked(session, idx) {
+			rec.Reason = "timeout"
+		}
+		q, ok := byID[id]
+		if !ok {
+			// Deleted during the round.
+			session.Answers = append(session.Answers, rec)
+			continue
+		}
+
+		order := optionOrder(session, idx, len(q.Options))
+		switch {
+		case rec.Answer == -1:
+			if countsAsIncorrect(rec) {
+				session.IncorrectAnswers++
+			}
+			session.Streak = 0
+		case storedIndex(order, rec.Answer) == q.CorrectAnswer:
+			rec.Correct, rec.Reason = true, "correct"
+			session.CorrectAnswers++
+			session.Streak++
+		default:
+			rec.Reason = "wrong_answer"
+			session.IncorrectAnswers++
+			session.Streak = 0
+		}
+		rec.Score = policy.Score(Attempt{
+			Correct:   rec.Correct,
+			Answered:  rec.Answer != -1,
+			Weight:    q.Weight,
+			Options:   len(q.Options),
+			Elapsed:   time.Duration(session.Spent[idx]) * time.Millisecond,
+			Limit:     models.QuestionTimeLimit * time.Second,
+			Streak:    session.Streak,
+			Deduction: s.settings.Lifelines.deduction(session, idx),
+		})
+		session.Score += rec.Score.Points
+		session.Answers = append(session.Answers, rec)
+		metrics.Answers.WithLabelValues(rec.Reason).Inc()
+	}
+
+	addPlayTime(session, time.Duration(spent)*time.Millisecond)
+	session.CurrentIndex = len(session.Questions)
+	s.setCurrentTime(session)
+	return nil
+}
+
+// commitView adds the time the current question has been shown to its total
+// and restarts the clock.
+func commitView(session *models.UserSession, now time.Time) {
+	if session.QuestionStartTime == nil {
+		return
+	}
+	session.Spent[session.CurrentIndex] += int(now.Sub(*session.QuestionStartTime).Milliseconds())
+	session.QuestionStartTime = &now
+}
+
+// questionLocked reports whether the question at idx used up its time.
+func questionLocked(session *models.UserSession, idx int) bool {
+	return session.NavigationTimer == models.TimerQuestion && session.Spent[idx] > models.ServerTimeLimit*1000
+}
+
+// nextOpenQuestion finds the next question after idx, wrapping around, whose
+// time is not up; -1 when there is none.
+func nextOpenQuestion(session *models.UserSession, idx int) int {
+	for k := 1; k < len(session.Questions); k++ {
+		next := (idx + k) % len(session.Questions)
+		if !questionLocked(session, next) {
+			return next
+		}
+	}
+	return -1
+}
@@ -0,0 +1,104 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"quiz_backend/models"
+	"quiz_backend/pkg/response"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrRoundPaused     = errors.New("round is paused")
+	ErrPausingDisabled = errors.New("pausing is not allowed")
+	ErrPauseLimit      = errors.New("no pauses left in this round")
+)
+
+// Pausing limits how often a round may be paused; a Limit of 0 turns pausing
+// off.
+type Pausing struct {
+	Limit int
+}
+
+func (p Pausing) left(session *models.UserSession) int {
+	return max(p.Limit-session.Pauses, 0)
+}
+
+// Pause stops the question and round timers of the session's round until
+// Resume. The current question is withheld meanwhile. Pausing a paused round
+// changes nothing.
+func (s *QuizService) Pause(ctx context.Context, session *models.UserSession) (models.StartResponse, error) {
+	if !session.HasActiveGame {
+		return models.StartResponse{}, ErrRoundFinished
+	}
+	if session.PausedAt == nil {
+		if s.settings.Pausing.Limit == 0 {
+			return models.StartResponse{}, ErrPausingDisabled
+		}
+		s.handleTimeout(session)
+		if !session.HasActiveGame {
+			if err := s.repo.UpdateSession(session); err != nil {
+				return models.StartResponse{}, err
+			}
+			return models.StartResponse{}, ErrRoundFinished
+		}
+		if s.settings.Pausing.left(session) == 0 {
+			return models.StartResponse{}, ErrPauseLimit
+		}
+
+		now := time.Now()
+		session.PausedAt = &now
+		session.Pauses++
+		if err := s.repo.UpdateSession(session); err != nil {
+			return models.StartResponse{}, err
+		}
+	}
+	return s.pausedResponse(session), nil
+}
+
+// Resume restarts the timers of a paused round where they stopped and returns
+// the current question like StartQuiz does.
+func (s *QuizService) Resume(ctx context.Context, session *models.UserSession, accept []language.Tag) (models.StartResponse, error) {
+	if !session.HasActiveGame {
+		return models.StartResponse{}, ErrRoundFinished
+	}
+	if session.PausedAt != nil {
+		// Moving the start of the question and the deadline by the length of
+		// the pause leaves the time already used unchanged.
+		paused := time.Since(*session.PausedAt)
+		session.QuestionStartTime = later(session.QuestionStartTime, paused)
+		session.RoundDeadline = later(session.RoundDeadline, paused)
+		session.PausedAt = nil
+		// StartQuiz only logs a failed save, which would leave the round
+		// paused without the player knowing.
+		if err := s.repo.UpdateSession(session); err != nil {
+			return models.StartResponse{}, err
+		}
+	}
+	return s.StartQuiz(ctx, session, accept)
+}
+
+func (s *QuizService) pausedResponse(session *models.UserSession) models.StartResponse {
+	resp := response.ToStartResponse(0, answerFeedback(session), session, nil)
+	resp.LifelinesLeft = s.settings.Lifelines.left(session)
+	resp.PausesLeft = s.settings.Pausing.left(session)
+	return resp
+}
+
+// addPlayTime adds d to the time the session has been played.
+func addPlayTime(session *models.UserSession, d time.Duration) {
+	// Sessions from before milliseconds were kept only have whole seconds.
+	session.TotalTimeMs = max(session.TotalTimeMs, int64(session.TotalTime)*1000)
+	session.TotalTimeMs += d.Milliseconds()
+	session.TotalTime = int(session.TotalTimeMs / 1000)
+}
+
+func later(t *time.Time, d time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	moved := t.Add(d)
+	return &moved
+}
@@ -0,0 +1,229 @@
+package quiz
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"quiz_backend/models"
+	"quiz_backend/pkg/response"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// sortColumns maps the public sort keys to question columns.
+var sortColumns = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"text":       "text",
+}
+
+// QuestionQuery describes one page of the admin question list. Without a
+// cursor the list is paged by offset (Page); with one it continues after (or
+// before) the row the cursor points at, which stays stable while rows change.
+// Relevance orders search results by match quality instead of Sort.
+type QuestionQuery struct {
+	Search      string
+	Relevance   bool
+	Option      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Sort        string
+	Desc        bool
+	Limit       int
+	Page        int
+	Cursor      *Cursor
+}
+
+// Cursor points at the first or last row of a page in a given ordering.
+type Cursor struct {
+	Sort     string `json:"s"`
+	Desc     bool   `json:"d,omitempty"`
+	Value    string `json:"v"`
+	ID       uint   `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+type QuestionPage struct {
+	Questions  []models.Question
+	Total      int64
+	Pages      int64
+	Page       int
+	NextCursor string
+	PrevCursor string
+	Highlights map[uint]models.SearchHighlight
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errInvalidCursor
+	}
+	if _, ok := sortColumns[c.Sort]; !ok {
+		return nil, errInvalidCursor
+	}
+	if _, err := cursorArg(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// cursorValue renders the sort key of q the way it is stored in a cursor.
+func cursorValue(q *models.Question, sort string) string {
+	switch sort {
+	case "created_at":
+		return q.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "updated_at":
+		return q.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "text":
+		return q.Text
+	default:
+		return strconv.FormatUint(uint64(q.ID), 10)
+	}
+}
+
+// cursorArg converts a cursor value back to the column's Go type.
+func cursorArg(c *Cursor) (any, error) {
+	switch c.Sort {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		// SQLite compares the stored text, which is written in local time.
+		return t.Local(), nil
+	case "text":
+		return c.Value, nil
+	default:
+		n, err := strconv.ParseUint(c.Value, 10, 64)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		return n, nil
+	}
+}
+
+// ParseQuestionQuery reads the list parameters from the query string and
+// reports every invalid one.
+func ParseQuestionQuery(v url.Values) (QuestionQuery, []response.FieldError) {
+	q := QuestionQuery{
+		Search: strings.TrimSpace(v.Get("search")),
+		Option: strings.TrimSpace(v.Get("option")),
+		Sort:   "id",
+		Limit:  defaultPageLimit,
+		Page:   1,
+	}
+	var errs []response.FieldError
+	invalid := func(field, msg string) {
+		errs = append(errs, response.FieldError{Field: field, Code: "invalid", Message: msg})
+	}
+
+	if p := v.Get("page"); p != "" {
+		if n, err := strconv.Atoi(p); err == nil && n > 0 {
+			q.Page = n
+		} else {
+			invalid("page", "must be a positive integer")
+		}
+	}
+	// Limits above the maximum are capped rather than rejected.
+	if l := v.Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			q.Limit = min(n, maxPageLimit)
+		} else {
+			invalid("limit", "must be a positive integer")
+		}
+	}
+
+	// Searches default to the best matches first.
+	q.Relevance = q.Search != ""
+	if s := v.Get("sort"); s != "" {
+		if s == "relevance" {
+			q.Relevance = true
+		} else if _, ok := sortColumns[s]; !ok {
+			invalid("sort", "must be one of relevance, created_at, updated_at, id, text")
+		} else {
+			q.Sort = s
+			q.Relevance = false
+		}
+	}
+	switch strings.ToLower(v.Get("order")) {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		invalid("order", "must be asc or desc")
+	}
+
+	for _, f := range []struct {
+		name string
+		dst  **time.Time
+		end  bool
+	}{
+		{"created_from", &q.CreatedFrom, false},
+		{"created_to", &q.CreatedTo, true},
+		{"updated_from", &q.UpdatedFrom, false},
+		{"updated_to", &q.UpdatedTo, true},
+	} {
+		raw := v.Get(f.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseDateBound(raw, f.end)
+		if err != nil {
+			invalid(f.name, "must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+			continue
+		}
+		t = t.Local()
+		*f.dst = &t
+	}
+
+	if c := v.Get("cursor"); c != "" {
+		cursor, err := decodeCursor(c)
+		if err != nil {
+			invalid("cursor", "is malformed")
+		} else {
+			q.Cursor = cursor
+			q.Relevance = false
+			q.Sort = cursor.Sort
+			q.Desc = cursor.Desc
+		}
+	}
+
+	return q, errs
+}
+
+// parseDateBound accepts RFC 3339 timestamps and plain dates. A plain date
+// used as an upper bound covers the whole day.
+func parseDateBound(s string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,80 @@
+package quiz
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestParseQuestionQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantPage  int
+		wantLimit int
+		wantSort  string
+		wantDesc  bool
+	}{
+		{"defaults", "", 1, defaultPageLimit, "id", false},
+		{"page and limit", "page=3&limit=25", 3, 25, "id", false},
+		{"limit capped", "limit=1000", 1, maxPageLimit, "id", false},
+		{"sort descending", "sort=created_at&order=DESC", 1, defaultPageLimit, "created_at", true},
+		{"cursor decides the order", "sort=text&cursor=" + Cursor{Sort: "updated_at", Desc: true, Value: "2026-01-02T03:04:05Z", ID: 7}.Encode(),
+			1, defaultPageLimit, "updated_at", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, errs := ParseQuestionQuery(v)
+			if errs != nil {
+				t.Fatalf("ParseQuestionQuery(%q) reported %+v", tt.query, errs)
+			}
+			if q.Page != tt.wantPage || q.Limit != tt.wantLimit || q.Sort != tt.wantSort || q.Desc != tt.wantDesc {
+				t.Errorf("ParseQuestionQuery(%q) = page %d, limit %d, sort %q, desc %t; want page %d, limit %d, sort %q, desc %t",
+					tt.query, q.Page, q.Limit, q.Sort, q.Desc, tt.wantPage, tt.wantLimit, tt.wantSort, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestParseQuestionQueryInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"page not a number", "page=abc", []string{"page"}},
+		{"page zero", "page=0", []string{"page"}},
+		{"limit negative", "limit=-5", []string{"limit"}},
+		{"limit not a number", "limit=ten", []string{"limit"}},
+		{"unknown sort", "sort=score", []string{"sort"}},
+		{"unknown order", "order=up", []string{"order"}},
+		{"bad date", "created_from=yesterday", []string{"created_from"}},
+		{"bad cursor", "cursor=not-a-cursor", []string{"cursor"}},
+		{"all reported", "page=-1&limit=x&order=up", []string{"page", "limit", "order"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, errs := ParseQuestionQuery(v)
+			var fields []string
+			for _, e := range errs {
+				if e.Code != "invalid" {
+					t.Errorf("%s: code %q, want %q", e.Field, e.Code, "invalid")
+				}
+				fields = append(fields, e.Field)
+			}
+			if !slices.Equal(fields, tt.want) {
+				t.Errorf("ParseQuestionQuery(%q) reported %v, want %v", tt.query, fields, tt.want)
+			}
+		})
+	}
+}
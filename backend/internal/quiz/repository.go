@@ -4,37 +4,151 @@ import (
 	mrand "math/rand"
 	"quiz_backend/models"
 	"quiz_backend/pkg/db"
+	"slices"
+
+	"gorm.io/gorm"
 )
 
 type QuizRepository struct {
 	Database *db.Db
+	searcher QuestionSearcher
 }
 
 func NewQuizRepository(database *db.Db) *QuizRepository {
-	return &QuizRepository{Database: database}
+	return &QuizRepository{Database: database, searcher: likeSearcher{}}
+}
+
+// UseFullTextSearch switches ?search= to the FTS5 index created by
+// db.SetupFullTextSearch.
+func (repo *QuizRepository) UseFullTextSearch() {
+	repo.searcher = ftsSearcher{}
 }
 
-func (repo *QuizRepository) GetQuestions(query string, page int, limit int) ([]models.Question, int64, int64, int, error) {
+func (repo *QuizRepository) GetQuestions(q QuestionQuery) (QuestionPage, error) {
 	var questions []models.Question
 	var total int64
 
-	offset := (page - 1) * limit
 	db := repo.Database.DB.Model(&models.Question{})
 
-	if query != "" {
-		db = db.Where("text LIKE ?", "%"+query+"%")
+	terms := parseSearch(q.Search)
+	if len(terms) > 0 {
+		db = repo.searcher.Filter(db, terms)
+	}
+	if q.Option != "" {
+		db = db.Where("EXISTS (SELECT 1 FROM json_each(questions.options) WHERE json_each.value LIKE ?)", "%"+q.Option+"%")
+	}
+	if q.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		db = db.Where("created_at <= ?", *q.CreatedTo)
+	}
+	if q.UpdatedFrom != nil {
+		db = db.Where("updated_at >= ?", *q.UpdatedFrom)
+	}
+	if q.UpdatedTo != nil {
+		db = db.Where("updated_at <= ?", *q.UpdatedTo)
 	}
 
 	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, 0, 0, err
+		return QuestionPage{}, err
+	}
+
+	if q.Relevance && len(terms) > 0 {
+		return repo.searchPage(db, q, terms, total)
 	}
 
-	pages := (total + int64(limit) - 1) / int64(limit)
-	if err := db.Offset(offset).Limit(limit).Find(&questions).Error; err != nil {
-		return nil, 0, 0, 0, err
+	col := sortColumns[q.Sort]
+	// Walking backwards from a cursor reads the rows in reverse order and
+	// flips them afterwards.
+	backward := q.Cursor != nil && q.Cursor.Backward
+	desc := q.Desc != backward
+
+	if q.Cursor != nil {
+		v, err := cursorArg(q.Cursor)
+		if err != nil {
+			return QuestionPage{}, err
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		db = db.Where("("+col+" "+op+" ?) OR ("+col+" = ? AND id "+op+" ?)", v, v, q.Cursor.ID)
+	} else {
+		db = db.Offset((q.Page - 1) * q.Limit)
+	}
+
+	dir := " ASC"
+	if desc {
+		dir = " DESC"
+	}
+	if err := db.Order(col + dir).Order("id" + dir).Limit(q.Limit + 1).Find(&questions).Error; err != nil {
+		return QuestionPage{}, err
 	}
 
-	return questions, total, pages, page, nil
+	more := len(questions) > q.Limit
+	if more {
+		questions = questions[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(questions)
+	}
+
+	page := QuestionPage{
+		Questions: questions,
+		Total:     total,
+		Pages:     (total + int64(q.Limit) - 1) / int64(q.Limit),
+	}
+	if q.Cursor == nil {
+		page.Page = q.Page
+	}
+
+	if len(questions) > 0 {
+		first, last := &questions[0], &questions[len(questions)-1]
+		hasNext := more || backward
+		hasPrev := (q.Cursor == nil && q.Page > 1) || (q.Cursor != nil && (!backward || more))
+		if hasNext {
+			page.NextCursor = Cursor{Sort: q.Sort, Desc: q.Desc, Value: cursorValue(last, q.Sort), ID: last.ID}.Encode()
+		}
+		if hasPrev {
+			page.PrevCursor = Cursor{Sort: q.Sort, Desc: q.Desc, Value: cursorValue(first, q.Sort), ID: first.ID, Backward: true}.Encode()
+		}
+	}
+
+	if len(terms) > 0 {
+		var err error
+		if page.Highlights, err = repo.searcher.Highlights(repo.Database.DB, terms, questions); err != nil {
+			return QuestionPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// searchPage returns the best matches first. Relevance has no stable column
+// to seek on, so these pages are offset-based only.
+func (repo *QuizRepository) searchPage(db *gorm.DB, q QuestionQuery, terms []searchTerm, total int64) (QuestionPage, error) {
+	var questions []models.Question
+	err := repo.searcher.OrderByRelevance(db, terms).
+		Offset((q.Page - 1) * q.Limit).
+		Limit(q.Limit).
+		Find(&questions).Error
+	if err != nil {
+		return QuestionPage{}, err
+	}
+
+	highlights, err := repo.searcher.Highlights(repo.Database.DB, terms, questions)
+	if err != nil {
+		return QuestionPage{}, err
+	}
+
+	return QuestionPage{
+		Questions:  questions,
+		Total:      total,
+		Pages:      (total + int64(q.Limit) - 1) / int64(q.Limit),
+		Page:       q.Page,
+		Highlights: highlights,
+	}, nil
 }
 
 func (repo *QuizRepository) GetQuestionById(id uint) (*models.Question, error) {
@@ -45,6 +159,15 @@ func (repo *QuizRepository) GetQuestionById(id uint) (*models.Question, error) {
 	return &q, nil
 }
 
+func (repo *QuizRepository) GetQuestionsByIds(ids []uint) ([]models.Question, error) {
+	var questions []models.Question
+	if len(ids) == 0 {
+		return questions, nil
+	}
+	err := repo.Database.DB.Where("id IN ?", ids).Find(&questions).Error
+	return questions, err
+}
+
 func (repo *QuizRepository) CreateQuestion(data *models.Question) (*models.Question, error) {
 	if err := repo.Database.DB.Create(data).Error; err != nil {
 		return nil, err
@@ -52,35 +175,59 @@ func (repo *QuizRepository) CreateQuestion(data *models.Question) (*models.Quest
 	return data, nil
 }
 
-func (repo *QuizRepository) UpdateQuestion(id uint, data *models.Question) (*models.Question, error) {
-	var question models.Question
-	if err := repo.Database.DB.First(&question, id).Error; err != nil {
-		return nil, err
+// UpdateQuestion overwrites the question only if it is still at version and
+// bumps the version. It returns db.ErrVersionConflict when another update won.
+func (repo *QuizRepository) UpdateQuestion(id uint, version uint, data *models.Question) (*models.Question, error) {
+	res := repo.Database.DB.Model(&models.Question{Model: gorm.Model{ID: id}}).
+		Where("version = ?", version).
+		Select("text", "format", "options", "correct_answer", "media", "option_media", "hint", "weight", "explanation", "references", "version").
+		Updates(&models.Question{
+			Text:          data.Text,
+			Format:        data.Format,
+			Options:       data.Options,
+			CorrectAnswer: data.CorrectAnswer,
+			Media:         data.Media,
+			OptionMedia:   data.OptionMedia,
+			Hint:          data.Hint,
+			Weight:        data.Weight,
+			Explanation:   data.Explanation,
+			References:    data.References,
+			Version:       version + 1,
+		})
+	if res.Error != nil {
+		return nil, res.Error
 	}
-
-	question.Text = data.Text
-	question.Options = data.Options
-	question.CorrectAnswer = data.CorrectAnswer
-
-	if err := repo.Database.DB.Save(&question).Error; err != nil {
-		return nil, err
+	if res.RowsAffected == 0 {
+		return nil, repo.missingOrConflict(id)
 	}
 
-	return &question, nil
+	return repo.GetQuestionById(id)
 }
 
-func (repo *QuizRepository) DeleteQuestion(id uint) (*models.Question, error) {
+// DeleteQuestion soft-deletes the question only if it is still at version.
+func (repo *QuizRepository) DeleteQuestion(id uint, version uint) (*models.Question, error) {
 	var q models.Question
 	if err := repo.Database.DB.First(&q, id).Error; err != nil {
 		return nil, err
 	}
 
-	if err := repo.Database.DB.Delete(&q).Error; err != nil {
-		return nil, err
+	res := repo.Database.DB.Where("version = ?", version).Delete(&q)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, repo.missingOrConflict(id)
 	}
 	return &q, nil
 }
 
+func (repo *QuizRepository) missingOrConflict(id uint) error {
+	if _, err := repo.GetQuestionById(id); err != nil {
+		return err
+	}
+	return db.ErrVersionConflict
+}
+
 func (repo *QuizRepository) GetRandomQuestions(count int) ([]models.Question, error) {
 	var questions []models.Question
 	var total int64
@@ -113,12 +260,31 @@ func (repo *QuizRepository) GetSessionByToken(token string) (*models.UserSession
 	return &s, err
 }
 
-func (repo *QuizRepository) GetActiveSessionByToken(token string) (*models.UserSession, error) {
-	var s models.UserSession
-	err := repo.Database.DB.Where("session_token = ? AND has_active_game = ?", token, true).First(&s).Error
-	return &s, err
+// UpdateSession saves the session only if nobody else saved it since it was
+// loaded, returning db.ErrVersionConflict otherwise.
+func (repo *QuizRepository) UpdateSession(s *models.UserSession) error {
+	version := s.Version
+	s.Version = version + 1
+
+	res := repo.Database.DB.Model(s).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(s)
+	if res.Error != nil {
+		s.Version = version
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		s.Version = version
+		return db.ErrVersionConflict
+	}
+	return nil
 }
 
-func (repo *QuizRepository) UpdateSession(s *models.UserSession) error {
-	return repo.Database.DB.Save(s).Error
+// Transaction runs fn with a repository bound to a single database transaction.
+func (repo *QuizRepository) Transaction(fn func(tx *QuizRepository) error) error {
+	return repo.Database.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&QuizRepository{Database: &db.Db{DB: tx}, searcher: repo.searcher})
+	})
 }
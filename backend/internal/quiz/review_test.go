@@ -0,0 +1,105 @@
+package quiz
+
+import (
+	"net/http"
+	"quiz_backend/models"
+	"slices"
+	"testing"
+)
+
+func TestReviewAfterCheckSession(t *testing.T) {
+	repo := newTestRepo(t, 3)
+	settings := testSettings(t)
+	settings.Feedback.Policy = FeedbackEndOfRound
+	p := newTestPlayer(t, repo, settings)
+
+	p.call(http.MethodGet, "/quiz/check-session", nil, nil)
+	var start models.StartResponse
+	if code := p.call(http.MethodGet, "/quiz/start", nil, &start); code != http.StatusOK {
+		t.Fatalf("start: status %d", code)
+	}
+	next := start.NextQuestion
+	for i := 0; next != nil; i++ {
+		var resp models.AnswerResponse
+		req := models.AnswerRequest{Answer: 0, Idx: i, Id: int(next.ID)}
+		if code := p.call(http.MethodPost, "/quiz/answer", req, &resp); code != http.StatusOK {
+			t.Fatalf("answer %d: status %d", i, code)
+		}
+		next = resp.NextQuestion
+	}
+	token := p.cookie.Value
+
+	var stats models.SessionStats
+	if code := p.call(http.MethodGet, "/quiz/check-session", nil, &stats); code != http.StatusOK {
+		t.Fatalf("check-session: status %d", code)
+	}
+	if stats.HasActiveGame || p.cookie.Value != token {
+		t.Fatalf("check-session replaced the finished round's session")
+	}
+
+	var review models.ReviewDTO
+	if code := p.call(http.MethodGet, "/quiz/review", nil, &review); code != http.StatusOK {
+		t.Fatalf("review after check-session: status %d", code)
+	}
+	if len(review.Answers) != 3 || review.TotalCorrect+review.TotalIncorrect != 3 {
+		t.Errorf("review has %d answers, %d correct and %d incorrect; want 3 answers in total",
+			len(review.Answers), review.TotalCorrect, review.TotalIncorrect)
+	}
+
+	// The next round takes the review's place.
+	if code := p.call(http.MethodGet, "/quiz/start", nil, &start); code != http.StatusOK || start.NextQuestion == nil {
+		t.Fatalf("next round: status %d, question %v", code, start.NextQuestion)
+	}
+	if s := p.session(repo); len(s.Answers) != 0 || s.EndTime != nil || len(s.Questions) != 3 {
+		t.Errorf("next round kept %d answers, end time %v and drew %d questions", len(s.Answers), s.EndTime, len(s.Questions))
+	}
+	if code := p.call(http.MethodGet, "/quiz/review", nil, nil); code == http.StatusOK {
+		t.Error("review of the running round is open under the end_of_round policy")
+	}
+}
+
+func TestReviewTotalsMatchSession(t *testing.T) {
+	repo := newTestRepo(t, 5)
+	p := newTestPlayer(t, repo, testSettings(t))
+
+	p.call(http.MethodGet, "/quiz/check-session", nil, nil)
+	var start models.StartResponse
+	if code := p.call(http.MethodGet, "/quiz/start", nil, &start); code != http.StatusOK {
+		t.Fatalf("start: status %d", code)
+	}
+
+	// Right, wrong, out of time on the client, skipped; the round goes on.
+	next := start.NextQuestion
+	for i, pick := range []func(q *models.QuestionDTO) int{
+		func(q *models.QuestionDTO) int { return slices.Index(q.Options, "right") },
+		func(q *models.QuestionDTO) int { return slices.Index(q.Options, "wrong 1") },
+		func(q *models.QuestionDTO) int { return -1 },
+	} {
+		var resp models.AnswerResponse
+		req := models.AnswerRequest{Answer: pick(next), Idx: i, Id: int(next.ID)}
+		if code := p.call(http.MethodPost, "/quiz/answer", req, &resp); code != http.StatusOK {
+			t.Fatalf("answer %d: status %d", i, code)
+		}
+		next = resp.NextQuestion
+	}
+	skip := models.LifelineRequest{Lifeline: models.LifelineSkip, Idx: 3, Id: int(next.ID)}
+	if code := p.call(http.MethodPost, "/quiz/lifeline", skip, nil); code != http.StatusOK {
+		t.Fatalf("skip: status %d", code)
+	}
+
+	var review models.ReviewDTO
+	if code := p.call(http.MethodGet, "/quiz/review", nil, &review); code != http.StatusOK {
+		t.Fatalf("review: status %d", code)
+	}
+	s := p.session(repo)
+	if review.TotalCorrect != s.CorrectAnswers || review.TotalIncorrect != s.IncorrectAnswers {
+		t.Errorf("review counts %d correct and %d incorrect, session %d and %d",
+			review.TotalCorrect, review.TotalIncorrect, s.CorrectAnswers, s.IncorrectAnswers)
+	}
+	if review.TotalCorrect != 1 || review.TotalIncorrect != 2 {
+		t.Errorf("review counts %d correct and %d incorrect, want 1 and 2", review.TotalCorrect, review.TotalIncorrect)
+	}
+	if got := review.Answers[2].Reason; got != "timeout" {
+		t.Errorf("answer -1 recorded as %q, want %q", got, "timeout")
+	}
+}
@@ -0,0 +1,218 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"quiz_backend/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ScoringPolicy turns the outcome of one answer into points. Policies are
+// looked up by name in the registry; a round records the name of the policy
+// it is scored by, so results stay interpretable after the configured policy
+// changes.
+type ScoringPolicy interface {
+	Name() string
+	Score(a Attempt) models.ScoreBreakdown
+}
+
+// Attempt is what a policy may base the points of an answer on.
+type Attempt struct {
+	Correct  bool
+	Answered bool // false when no option was chosen before time ran out
+	Weight   int  // question weight, 0 counts as 1
+	Options  int  // number of options of the question
+	Elapsed  time.Duration
+	Limit    time.Duration // time the player had for the question
+	Streak   int           // correct answers in a row, including this one if correct
+	// Deduction is the share of credit the player gave up for the question
+	// by using lifelines, from 0 (none) to 1 (all of it). Every built-in
+	// policy takes it off the points of a correct answer as a penalty.
+	Deduction float64
+}
+
+// ScoringParams holds the parameters the built-in policies share. Each policy
+// documents the ones it uses.
+type ScoringParams struct {
+	BasePoints    int
+	SpeedBonus    int
+	StreakStep    float64
+	MaxMultiplier float64
+	WrongPenalty  int
+}
+
+// ScoringFactory builds a policy from the configured parameters.
+type ScoringFactory func(ScoringParams) ScoringPolicy
+
+var (
+	scoringMu       sync.RWMutex
+	scoringRegistry = map[string]ScoringFactory{}
+)
+
+// RegisterScoringPolicy makes a policy available under name. It panics when
+// the name is taken, like http.Handle does for a pattern.
+func RegisterScoringPolicy(name string, factory ScoringFactory) {
+	scoringMu.Lock()
+	defer scoringMu.Unlock()
+	if _, dup := scoringRegistry[name]; dup {
+		panic("quiz: scoring policy " + name + " registered twice")
+	}
+	scoringRegistry[name] = factory
+}
+
+// NewScoringPolicy builds the policy registered under name.
+func NewScoringPolicy(name string, params ScoringParams) (ScoringPolicy, error) {
+	scoringMu.RLock()
+	factory, ok := scoringRegistry[name]
+	scoringMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring policy %q, have %v", name, ScoringPolicies())
+	}
+	return factory(params), nil
+}
+
+// ScoringPolicies lists the registered policy names in order.
+func ScoringPolicies() []string {
+	scoringMu.RLock()
+	defer scoringMu.RUnlock()
+	names := make([]string, 0, len(scoringRegistry))
+	for name := range scoringRegistry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DefaultScoringPolicy scores rounds that were started before policies were
+// recorded.
+const DefaultScoringPolicy = "speed_weighted"
+
+func init() {
+	RegisterScoringPolicy("flat", func(p ScoringParams) ScoringPolicy { return flatScoring{p} })
+	RegisterScoringPolicy("speed_weighted", func(p ScoringParams) ScoringPolicy { return speedWeightedScoring{p} })
+	RegisterScoringPolicy("kahoot", func(ScoringParams) ScoringPolicy { return kahootScoring{} })
+	RegisterScoringPolicy("partial_credit", func(p ScoringParams) ScoringPolicy { return partialCreditScoring{p} })
+}
+
+// flatScoring gives BasePoints times the weight for a correct answer and
+// takes WrongPenalty times the weight for a wrong one.
+type flatScoring struct{ p ScoringParams }
+
+func (flatScoring) Name() string { return "flat" }
+
+func (s flatScoring) Score(a Attempt) models.ScoreBreakdown {
+	switch {
+	case a.Correct:
+		return deduct(models.ScoreBreakdown{Base: s.p.BasePoints * questionWeight(a.Weight), Multiplier: 1}, a.Deduction)
+	case a.Answered:
+		return total(models.ScoreBreakdown{Multiplier: 1, Penalty: s.p.WrongPenalty * questionWeight(a.Weight)})
+	default:
+		return total(models.ScoreBreakdown{Multiplier: 1})
+	}
+}
+
+// speedWeightedScoring adds to the flat points a speed bonus of up to
+// SpeedBonus that shrinks linearly over the time limit, and multiplies both
+// by a streak multiplier that starts at 1 and grows by StreakStep for every
+// further correct answer in a row, up to MaxMultiplier.
+type speedWeightedScoring struct{ p ScoringParams }
+
+func (speedWeightedScoring) Name() string { return "speed_weighted" }
+
+func (s speedWeightedScoring) Score(a Attempt) models.ScoreBreakdown {
+	if !a.Correct {
+		return flatScoring(s).Score(a)
+	}
+	b := models.ScoreBreakdown{
+		Base:       s.p.BasePoints * questionWeight(a.Weight),
+		SpeedBonus: speedShare(s.p.SpeedBonus, a.Elapsed, a.Limit),
+		Multiplier: streakMultiplier(s.p.StreakStep, s.p.MaxMultiplier, a.Streak),
+	}
+	b.StreakBonus = int(math.Round(float64(b.Base+b.SpeedBonus)*b.Multiplier)) - b.Base - b.SpeedBonus
+	return deduct(b, a.Deduction)
+}
+
+// kahootScoring follows the popular game show formula: a correct answer is
+// worth up to 1000 points, half of them fixed and half shrinking with the
+// time taken, plus 100 for each further correct answer in a row up to 500.
+// Points scale with the weight; wrong answers cost nothing.
+type kahootScoring struct{}
+
+func (kahootScoring) Name() string { return "kahoot" }
+
+func (kahootScoring) Score(a Attempt) models.ScoreBreakdown {
+	if !a.Correct {
+		return total(models.ScoreBreakdown{Multiplier: 1})
+	}
+	w := questionWeight(a.Weight)
+	return deduct(models.ScoreBreakdown{
+		Base:        500 * w,
+		SpeedBonus:  speedShare(500, a.Elapsed, a.Limit) * w,
+		Multiplier:  1,
+		StreakBonus: min(100*max(a.Streak-1, 0), 500),
+	}, a.Deduction)
+}
+
+// partialCreditScoring gives BasePoints times the weight for a correct
+// answer, so lifelines leave a share of it. With a WrongPenalty it uses
+// formula scoring: a wrong answer costs the base points divided by the number
+// of wrong options, so guessing at random averages to zero.
+type partialCreditScoring struct{ p ScoringParams }
+
+func (partialCreditScoring) Name() string { return "partial_credit" }
+
+func (s partialCreditScoring) Score(a Attempt) models.ScoreBreakdown {
+	full := s.p.BasePoints * questionWeight(a.Weight)
+	switch {
+	case a.Correct:
+		return deduct(models.ScoreBreakdown{Base: full, Multiplier: 1}, a.Deduction)
+	case a.Answered && s.p.WrongPenalty > 0 && a.Options > 1:
+		return total(models.ScoreBreakdown{Multiplier: 1, Penalty: int(math.Round(float64(full) / float64(a.Options-1)))})
+	default:
+		return total(models.ScoreBreakdown{Multiplier: 1})
+	}
+}
+
+// deduct turns the share d of what a correct answer earned into a penalty
+// and fills in the points.
+func deduct(b models.ScoreBreakdown, d float64) models.ScoreBreakdown {
+	d = math.Min(math.Max(d, 0), 1)
+	b.Penalty += int(math.Round(float64(b.Base+b.SpeedBonus+b.StreakBonus) * d))
+	return total(b)
+}
+
+// total fills in the points from the components.
+func total(b models.ScoreBreakdown) models.ScoreBreakdown {
+	b.Points = b.Base + b.SpeedBonus + b.StreakBonus - b.Penalty
+	return b
+}
+
+// streakMultiplier is 1 for the first correct answer in a row, rounded to
+// hundredths so it reads well in breakdowns.
+func streakMultiplier(step, ceiling float64, streak int) float64 {
+	m := math.Min(1+step*float64(max(streak-1, 0)), math.Max(ceiling, 1))
+	return math.Round(m*100) / 100
+}
+
+// speedShare shrinks bonus linearly from an instant answer to one at the time
+// limit; answers in the grace period get nothing.
+func speedShare(bonus int, elapsed, limit time.Duration) int {
+	if limit <= 0 || elapsed >= limit {
+		return 0
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return int(math.Round(float64(bonus) * float64(limit-elapsed) / float64(limit)))
+}
+
+// questionWeight treats the zero weight of questions stored before weights
+// existed as 1.
+func questionWeight(w int) int {
+	if w <= 0 {
+		return 1
+	}
+	return w
+}
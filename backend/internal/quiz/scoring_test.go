@@ -0,0 +1,111 @@
+package quiz
+
+import (
+	"quiz_backend/models"
+	"testing"
+	"time"
+)
+
+var testParams = ScoringParams{BasePoints: 100, SpeedBonus: 50, StreakStep: 0.1, MaxMultiplier: 2, WrongPenalty: 25}
+
+const testLimit = 30 * time.Second
+
+func TestScoringPolicies(t *testing.T) {
+	tests := []struct {
+		policy  string
+		name    string
+		attempt Attempt
+		want    models.ScoreBreakdown
+	}{
+		{"flat", "correct", Attempt{Correct: true, Answered: true, Weight: 1, Options: 4, Streak: 1},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Points: 100}},
+		{"flat", "weighted", Attempt{Correct: true, Answered: true, Weight: 3, Options: 4, Streak: 1},
+			models.ScoreBreakdown{Base: 300, Multiplier: 1, Points: 300}},
+		{"flat", "unset weight counts as 1", Attempt{Correct: true, Answered: true, Options: 4, Streak: 1},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Points: 100}},
+		{"flat", "after a hint", Attempt{Correct: true, Answered: true, Weight: 1, Options: 4, Streak: 1, Deduction: 0.25},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Penalty: 25, Points: 75}},
+		{"flat", "wrong", Attempt{Answered: true, Weight: 2, Options: 4},
+			models.ScoreBreakdown{Multiplier: 1, Penalty: 50, Points: -50}},
+		{"flat", "timed out", Attempt{Weight: 2, Options: 4},
+			models.ScoreBreakdown{Multiplier: 1}},
+
+		{"speed_weighted", "instant", Attempt{Correct: true, Answered: true, Weight: 1, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 100, SpeedBonus: 50, Multiplier: 1, Points: 150}},
+		{"speed_weighted", "half time", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: 15 * time.Second, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 100, SpeedBonus: 25, Multiplier: 1, Points: 125}},
+		{"speed_weighted", "in grace period", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: 31 * time.Second, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Points: 100}},
+		{"speed_weighted", "third in a row", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: 15 * time.Second, Limit: testLimit, Streak: 3},
+			models.ScoreBreakdown{Base: 100, SpeedBonus: 25, Multiplier: 1.2, StreakBonus: 25, Points: 150}},
+		{"speed_weighted", "streak capped", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: testLimit, Limit: testLimit, Streak: 50},
+			models.ScoreBreakdown{Base: 100, Multiplier: 2, StreakBonus: 100, Points: 200}},
+		{"speed_weighted", "after 50/50", Attempt{Correct: true, Answered: true, Weight: 1, Limit: testLimit, Streak: 1, Deduction: 0.5},
+			models.ScoreBreakdown{Base: 100, SpeedBonus: 50, Multiplier: 1, Penalty: 75, Points: 75}},
+		{"speed_weighted", "wrong", Attempt{Answered: true, Weight: 1, Limit: testLimit},
+			models.ScoreBreakdown{Multiplier: 1, Penalty: 25, Points: -25}},
+
+		{"kahoot", "instant", Attempt{Correct: true, Answered: true, Weight: 1, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 500, SpeedBonus: 500, Multiplier: 1, Points: 1000}},
+		{"kahoot", "at the limit", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: testLimit, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 500, Multiplier: 1, Points: 500}},
+		{"kahoot", "double points", Attempt{Correct: true, Answered: true, Weight: 2, Elapsed: 15 * time.Second, Limit: testLimit, Streak: 1},
+			models.ScoreBreakdown{Base: 1000, SpeedBonus: 500, Multiplier: 1, Points: 1500}},
+		{"kahoot", "streak bonus capped", Attempt{Correct: true, Answered: true, Weight: 1, Elapsed: testLimit, Limit: testLimit, Streak: 10},
+			models.ScoreBreakdown{Base: 500, Multiplier: 1, StreakBonus: 500, Points: 1000}},
+		{"kahoot", "after a hint", Attempt{Correct: true, Answered: true, Weight: 1, Limit: testLimit, Streak: 1, Deduction: 0.25},
+			models.ScoreBreakdown{Base: 500, SpeedBonus: 500, Multiplier: 1, Penalty: 250, Points: 750}},
+		{"kahoot", "wrong costs nothing", Attempt{Answered: true, Weight: 1, Limit: testLimit},
+			models.ScoreBreakdown{Multiplier: 1}},
+
+		{"partial_credit", "full credit", Attempt{Correct: true, Answered: true, Weight: 1, Options: 4, Streak: 1},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Points: 100}},
+		{"partial_credit", "quarter deducted", Attempt{Correct: true, Answered: true, Weight: 2, Options: 4, Streak: 1, Deduction: 0.25},
+			models.ScoreBreakdown{Base: 200, Multiplier: 1, Penalty: 50, Points: 150}},
+		{"partial_credit", "deduction clamped", Attempt{Correct: true, Answered: true, Weight: 1, Options: 4, Streak: 1, Deduction: 2},
+			models.ScoreBreakdown{Base: 100, Multiplier: 1, Penalty: 100}},
+		{"partial_credit", "formula scoring", Attempt{Answered: true, Weight: 1, Options: 4},
+			models.ScoreBreakdown{Multiplier: 1, Penalty: 33, Points: -33}},
+		{"partial_credit", "timed out", Attempt{Weight: 1, Options: 4},
+			models.ScoreBreakdown{Multiplier: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+"/"+tt.name, func(t *testing.T) {
+			policy, err := NewScoringPolicy(tt.policy, testParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := policy.Score(tt.attempt); got != tt.want {
+				t.Errorf("Score(%+v) = %+v, want %+v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoringPolicyNames(t *testing.T) {
+	for _, name := range ScoringPolicies() {
+		policy, err := NewScoringPolicy(name, testParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.Name() != name {
+			t.Errorf("policy registered as %q reports name %q", name, policy.Name())
+		}
+	}
+}
+
+func TestNewScoringPolicyUnknown(t *testing.T) {
+	if _, err := NewScoringPolicy("golf", testParams); err == nil {
+		t.Error("NewScoringPolicy(\"golf\") succeeded, want an error")
+	}
+}
+
+func TestRegisterScoringPolicyTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a taken name did not panic")
+		}
+	}()
+	RegisterScoringPolicy("flat", func(p ScoringParams) ScoringPolicy { return flatScoring{p} })
+}
@@ -0,0 +1,182 @@
+package quiz
+
+import (
+	"html"
+	"quiz_backend/models"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Highlight markers inserted around matches before the surrounding text is
+// HTML-escaped; they are then swapped for <mark> tags.
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+// searchTerm is one word or quoted phrase of a ?search= query. Words match as
+// prefixes ("marg" finds "margin"), phrases match as written.
+type searchTerm struct {
+	Text   string
+	Phrase bool
+}
+
+// QuestionSearcher implements ?search= on the question list.
+type QuestionSearcher interface {
+	// Filter keeps the questions whose text or options match every term.
+	Filter(tx *gorm.DB, terms []searchTerm) *gorm.DB
+	// OrderByRelevance puts the best matches first, ties by ID. It replaces
+	// any other ordering since gorm does not merge expression orders.
+	OrderByRelevance(tx *gorm.DB, terms []searchTerm) *gorm.DB
+	// Highlights returns the matched fragments of questions keyed by ID.
+	Highlights(tx *gorm.DB, terms []searchTerm, questions []models.Question) (map[uint]models.SearchHighlight, error)
+}
+
+// parseSearch splits input into words and "quoted phrases". A trailing * on
+// a word is accepted and ignored since words are prefix matches anyway.
+func parseSearch(input string) []searchTerm {
+	var terms []searchTerm
+	for {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return terms
+		}
+
+		if input[0] == '"' {
+			end := strings.IndexByte(input[1:], '"')
+			if end < 0 {
+				end = len(input) - 1
+			}
+			if phrase := strings.Join(strings.Fields(input[1:end+1]), " "); phrase != "" {
+				terms = append(terms, searchTerm{Text: phrase, Phrase: true})
+			}
+			input = input[min(end+2, len(input)):]
+			continue
+		}
+
+		end := strings.IndexFunc(input, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(input)
+		}
+		if word := strings.TrimRight(input[:end], "*"); word != "" {
+			terms = append(terms, searchTerm{Text: word})
+		}
+		input = input[end:]
+	}
+}
+
+// ftsSearcher uses the SQLite FTS5 index questions_fts.
+type ftsSearcher struct{}
+
+func (ftsSearcher) match(terms []searchTerm) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		quoted := `"` + strings.ReplaceAll(t.Text, `"`, `""`) + `"`
+		if !t.Phrase {
+			quoted += "*"
+		}
+		parts[i] = quoted
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s ftsSearcher) Filter(tx *gorm.DB, terms []searchTerm) *gorm.DB {
+	return tx.Where("questions.id IN (SELECT rowid FROM questions_fts WHERE questions_fts MATCH ?)", s.match(terms))
+}
+
+func (s ftsSearcher) OrderByRelevance(tx *gorm.DB, terms []searchTerm) *gorm.DB {
+	// bm25 weights: a hit in the question text counts ten times a hit in an option.
+	return tx.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  "(SELECT bm25(questions_fts, 10.0, 1.0) FROM questions_fts WHERE questions_fts MATCH ? AND rowid = questions.id), questions.id",
+		Vars: []any{s.match(terms)},
+	}})
+}
+
+func (s ftsSearcher) Highlights(tx *gorm.DB, terms []searchTerm, questions []models.Question) (map[uint]models.SearchHighlight, error) {
+	ids := make([]uint, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].ID
+	}
+
+	var rows []struct {
+		ID      uint
+		Text    string
+		Options string
+	}
+	err := tx.Raw(`SELECT rowid AS id,
+			snippet(questions_fts, 0, char(2), char(3), '…', 16) AS text,
+			snippet(questions_fts, 1, char(2), char(3), '…', 16) AS options
+		FROM questions_fts WHERE questions_fts MATCH ? AND rowid IN ?`, s.match(terms), ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[uint]models.SearchHighlight, len(rows))
+	for _, row := range rows {
+		out[row.ID] = models.SearchHighlight{
+			Text:    renderHighlight(row.Text),
+			Options: renderHighlight(row.Options),
+		}
+	}
+	return out, nil
+}
+
+// likeSearcher is the portable fallback: substring matching on the text and
+// the serialized options, ranking questions that match in the text first.
+type likeSearcher struct{}
+
+func (likeSearcher) Filter(tx *gorm.DB, terms []searchTerm) *gorm.DB {
+	for _, t := range terms {
+		pattern := likePattern(t.Text)
+		tx = tx.Where(`(text LIKE ? ESCAPE '\' OR options LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	return tx
+}
+
+func (likeSearcher) OrderByRelevance(tx *gorm.DB, terms []searchTerm) *gorm.DB {
+	return tx.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  `CASE WHEN text LIKE ? ESCAPE '\' THEN 0 ELSE 1 END, questions.id`,
+		Vars: []any{likePattern(terms[0].Text)},
+	}})
+}
+
+func (likeSearcher) Highlights(_ *gorm.DB, terms []searchTerm, questions []models.Question) (map[uint]models.SearchHighlight, error) {
+	alts := make([]string, len(terms))
+	for i, t := range terms {
+		alts[i] = regexp.QuoteMeta(t.Text)
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(alts, "|"))
+	mark := func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(m string) string { return markStart + m + markEnd })
+	}
+
+	out := make(map[uint]models.SearchHighlight, len(questions))
+	for _, q := range questions {
+		out[q.ID] = models.SearchHighlight{
+			Text:    renderHighlight(mark(q.Text)),
+			Options: renderHighlight(mark(strings.Join(q.Options, " "))),
+		}
+	}
+	return out, nil
+}
+
+// renderHighlight HTML-escapes s and turns the match markers into <mark>
+// tags. Fragments without a match are dropped.
+func renderHighlight(s string) string {
+	if !strings.Contains(s, markStart) {
+		return ""
+	}
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markStart, "<mark>")
+	return strings.ReplaceAll(s, markEnd, "</mark>")
+}
+
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
@@ -1,92 +1,399 @@
 package quiz
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"quiz_backend/internal/session"
 	"quiz_backend/models"
+	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/metrics"
 	"quiz_backend/pkg/response"
 	"time"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	maxSubmitAttempts = 3
+	questionsPerRound = session.QuestionsPerRound
+)
+
+var (
+	ErrRoundFinished  = errors.New("quiz round already completed")
+	ErrAnswerMismatch = errors.New("answer does not match the current question")
 )
 
 type QuizService struct {
-	repo *QuizRepository
+	repo     *QuizRepository
+	settings Settings
 }
 
-func NewQuizService(repo *QuizRepository) *QuizService {
-	return &QuizService{repo: repo}
+func NewQuizService(repo *QuizRepository, settings Settings) *QuizService {
+	return &QuizService{repo: repo, settings: settings}
 }
 
-func (s *QuizService) GetSession(session *models.UserSession) models.SessionStats {
+func (s *QuizService) GetSession(ctx context.Context, session *models.UserSession) models.SessionStats {
 	s.handleTimeout(session)
-	s.repo.UpdateSession(session)
-	return response.ToCheckResponse(session)
+	s.saveSession(ctx, session)
+	return response.ToCheckResponse(session, answerFeedback(session))
 }
 
-func (s *QuizService) StartQuiz(session *models.UserSession) (models.StartResponse, error) {
-	var nextQ *models.Question
-	nextQ, _ = s.repo.GetQuestionById(session.Questions[session.CurrentIndex])
-
+// StartQuiz starts a round or resumes the current one. The question comes in
+// the locale that best matches the session locale or, failing that, accept.
+// A round started after a finished one gets fresh questions.
+func (s *QuizService) StartQuiz(ctx context.Context, session *models.UserSession, accept []language.Tag) (models.StartResponse, error) {
 	if !session.HasActiveGame {
+		if session.EndTime != nil {
+			if err := s.drawQuestions(session); err != nil {
+				return models.StartResponse{}, err
+			}
+		}
+		session.Answers = nil
+		session.Lifelines = nil
+		session.Score = 0
+		session.Streak = 0
+		session.PausedAt = nil
+		session.Pauses = 0
+		s.settings.Feedback.apply(session)
+		session.ScoringPolicy = s.settings.Scoring.Name()
+		s.settings.Navigation.apply(session)
+		session.RoundDeadline = s.roundDeadline(session, time.Now())
+		if err := s.shuffleOptions(session); err != nil {
+			logger.FromContext(ctx).Error("shuffle options, showing them in stored order", "err", err)
+			session.OptionOrder = nil
+		}
 		s.setCurrentTime(session)
+		metrics.RoundsStarted.Inc()
 	}
 	session.HasActiveGame = true
-	_, timeLimit := s.handleTimeout(session)
+	if session.PausedAt != nil {
+		s.saveSession(ctx, session)
+		return s.pausedResponse(session), nil
+	}
+	_, timeLimit, _ := s.handleTimeout(session)
+
+	nextQ := s.currentQuestion(ctx, s.repo, session, localePrefs(session, accept))
 
-	s.repo.UpdateSession(session)
-	return response.ToStartResponse(timeLimit, session, nextQ), nil
+	s.saveSession(ctx, session)
+	resp := response.ToStartResponse(timeLimit, answerFeedback(session), session, nextQ)
+	issueDeadline(session, resp.NextQuestion)
+	resp.LifelinesLeft = s.settings.Lifelines.left(session)
+	resp.PausesLeft = s.settings.Pausing.left(session)
+	return resp, nil
 }
 
-func (s *QuizService) ProcessAnswer(session *models.UserSession, answer int) (models.AnswerResponse, error) {
-	q, err := s.repo.GetQuestionById(session.Questions[session.CurrentIndex])
+// SubmitAnswer records the answer of the session identified by token. The
+// session is re-read and saved inside one transaction with a version check, so
+// concurrent submissions are serialised; a retried submission for a question
+// that already has a result gets that original result back. The next question
+// is localized as in StartQuiz.
+func (s *QuizService) SubmitAnswer(ctx context.Context, token string, req models.AnswerRequest, accept []language.Tag) (models.AnswerResponse, error) {
+	var resp models.AnswerResponse
+	var err error
+	for attempt := 0; attempt < maxSubmitAttempts; attempt++ {
+		err = s.repo.Transaction(func(tx *QuizRepository) error {
+			session, err := tx.GetSessionByToken(token)
+			if err != nil {
+				return err
+			}
+
+			if rec := findAnswer(session, req); rec != nil {
+				resp, err = s.replayAnswer(ctx, tx, session, rec, localePrefs(session, accept))
+				return err
+			}
+
+			if !session.HasActiveGame {
+				return ErrRoundFinished
+			}
+			if session.PausedAt != nil {
+				return ErrRoundPaused
+			}
+			if req.Idx != session.CurrentIndex || req.Id < 0 || uint(req.Id) != session.Questions[session.CurrentIndex] {
+				return ErrAnswerMismatch
+			}
+
+			if freeNavigation(session) {
+				resp, err = s.saveDraft(ctx, tx, session, req.Answer, localePrefs(session, accept))
+			} else {
+				resp, err = s.processAnswer(ctx, tx, session, req.Answer, localePrefs(session, accept))
+			}
+			if err != nil {
+				return err
+			}
+			return tx.UpdateSession(session)
+		})
+		if !errors.Is(err, db.ErrVersionConflict) {
+			break
+		}
+		logger.FromContext(ctx).Debug("session changed concurrently, retrying answer", "attempt", attempt+1)
+	}
+	return resp, err
+}
+
+func (s *QuizService) processAnswer(ctx context.Context, repo *QuizRepository, session *models.UserSession, answer int, prefs []language.Tag) (models.AnswerResponse, error) {
+	idx := session.CurrentIndex
+	q, err := repo.GetQuestionById(session.Questions[idx])
 	if err != nil {
 		return models.AnswerResponse{}, err
 	}
 
-	timedOut, _ := s.handleTimeout(session)
-	reason := ""
-
-	correct := false
+	timedOut, _, elapsed := s.handleTimeout(session)
+	order := optionOrder(session, idx, len(q.Options))
 
-	if timedOut {
-		reason = "timeout"
-	} else {
-		if answer == q.CorrectAnswer {
-			correct = true
-			reason = "correct"
+	if !timedOut {
+		rec := models.AnswerRecord{Idx: idx, QuestionID: q.ID, Answer: answer}
+		if storedIndex(order, answer) == q.CorrectAnswer {
+			rec.Correct = true
+			rec.Reason = "correct"
 			session.CorrectAnswers++
-		} else if answer != -1 {
-			reason = "wrong_answer"
+			session.Streak++
+		} else {
+			rec.Reason = "wrong_answer"
+			// -1 is what the client sends when its own countdown ran out.
+			if answer == -1 {
+				rec.Reason = "timeout"
+			}
 			session.IncorrectAnswers++
+			session.Streak = 0
 		}
+		rec.Score = s.scoringFor(session).Score(Attempt{
+			Correct:  rec.Correct,
+			Answered: answer != -1,
+			Weight:   q.Weight,
+			Options:  len(q.Options),
+			Elapsed:  elapsed,
+			Limit:    models.QuestionTimeLimit * time.Second,
+			Streak:   session.Streak,
+			// Lifelines used on the question cost a share of the points.
+			Deduction: s.settings.Lifelines.deduction(session, idx),
+		})
+		session.Score += rec.Score.Points
+		addPlayTime(session, elapsed)
 		session.CurrentIndex++
+		session.Answers = append(session.Answers, rec)
+		metrics.Answers.WithLabelValues(rec.Reason).Inc()
 	}
 
-	var nextQ *models.Question
+	// On a timeout the record is the one handleTimeout added.
+	rec := answerAt(session, idx)
 	s.setCurrentTime(session)
+	nextQ := s.currentQuestion(ctx, repo, session, prefs)
 
-	if session.HasActiveGame {
-		nextQ, _ = s.repo.GetQuestionById(session.Questions[session.CurrentIndex])
+	resp := response.ToAnswerResponse(q, rec, shownIndex(order, q.CorrectAnswer), answerFeedback(session), session, nextQ)
+	issueDeadline(session, resp.NextQuestion)
+	return resp, nil
+}
+
+// scoringFor returns the policy the session's round is scored by. Should that
+// policy no longer be registered, the configured one takes over.
+func (s *QuizService) scoringFor(session *models.UserSession) ScoringPolicy {
+	name := cmp.Or(session.ScoringPolicy, DefaultScoringPolicy)
+	if name == s.settings.Scoring.Name() {
+		return s.settings.Scoring
+	}
+	policy, err := NewScoringPolicy(name, s.settings.ScoringParams)
+	if err != nil {
+		return s.settings.Scoring
 	}
+	return policy
+}
 
-	s.repo.UpdateSession(session)
-	return response.ToAnswerResponse(correct, q.CorrectAnswer, reason, session, nextQ), nil
+// replayAnswer rebuilds the response of an answer that was already recorded.
+func (s *QuizService) replayAnswer(ctx context.Context, repo *QuizRepository, session *models.UserSession, rec *models.AnswerRecord, prefs []language.Tag) (models.AnswerResponse, error) {
+	q, err := repo.GetQuestionById(rec.QuestionID)
+	if err != nil {
+		return models.AnswerResponse{}, err
+	}
+	order := optionOrder(session, rec.Idx, len(q.Options))
+	nextQ := s.currentQuestion(ctx, repo, session, prefs)
+	resp := response.ToAnswerResponse(q, *rec, shownIndex(order, q.CorrectAnswer), answerFeedback(session), session, nextQ)
+	issueDeadline(session, resp.NextQuestion)
+	return resp, nil
 }
 
-func (s *QuizService) handleTimeout(session *models.UserSession) (timedOut bool, timeLimit int) {
+// Review returns the breakdown of the session's latest round, localized as in
+// StartQuiz. It fails with a *ReviewLockedError while the round's feedback
+// policy keeps the results hidden.
+func (s *QuizService) Review(ctx context.Context, session *models.UserSession, accept []language.Tag) (models.ReviewDTO, error) {
+	s.handleTimeout(session)
+	s.saveSession(ctx, session)
+
+	if open, reason := reviewOpen(session, time.Now()); !open {
+		return models.ReviewDTO{}, &ReviewLockedError{Reason: reason}
+	}
+
+	questions, err := s.repo.GetQuestionsByIds(answeredIDs(session))
+	if err != nil {
+		return models.ReviewDTO{}, err
+	}
+	byID := make(map[uint]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+
+	prefs := localePrefs(session, accept)
+	review := models.ReviewDTO{
+		FeedbackPolicy: string(sessionPolicy(session)),
+		HasActiveGame:  session.HasActiveGame,
+		Score:          response.ToScoreDTO(session),
+		Answers:        make([]models.ReviewItemDTO, 0, len(session.Answers)),
+	}
+	for _, rec := range session.Answers {
+		switch {
+		case rec.Correct:
+			review.TotalCorrect++
+		case countsAsIncorrect(rec):
+			review.TotalIncorrect++
+		}
+		q, ok := byID[rec.QuestionID]
+		if !ok {
+			// Deleted since the round; its record still counts.
+			continue
+		}
+		order := optionOrder(session, rec.Idx, len(q.Options))
+		shown := presentOptions(localize(s.repo, s.settings.Locales, q, prefs), order)
+		review.Answers = append(review.Answers, models.ReviewItemDTO{
+			Idx:             rec.Idx,
+			QuestionID:      rec.QuestionID,
+			Text:            shown.Text,
+			Options:         shown.Options,
+			Answer:          rec.Answer,
+			CorrectAnswer:   shownIndex(order, q.CorrectAnswer),
+			Correct:         rec.Correct,
+			Reason:          rec.Reason,
+			ExplanationHTML: response.ExplanationHTML(q),
+			References:      q.References,
+			Points:          rec.Score,
+		})
+	}
+	return review, nil
+}
+
+// answeredIDs lists the questions the session has results for.
+func answeredIDs(session *models.UserSession) []uint {
+	ids := make([]uint, len(session.Answers))
+	for i, rec := range session.Answers {
+		ids[i] = rec.QuestionID
+	}
+	return ids
+}
+
+// currentQuestion loads the question the session is on, localized for prefs
+// and with its options in the session's order for it.
+func (s *QuizService) currentQuestion(ctx context.Context, repo *QuizRepository, session *models.UserSession, prefs []language.Tag) *models.Question {
+	if !session.HasActiveGame {
+		return nil
+	}
+	q, err := repo.GetQuestionById(session.Questions[session.CurrentIndex])
+	if err != nil {
+		logger.FromContext(ctx).Error("load current question",
+			"question_id", session.Questions[session.CurrentIndex], "err", err)
+		return nil
+	}
+	q = localize(repo, s.settings.Locales, q, prefs)
+	return presentOptions(q, optionOrder(session, session.CurrentIndex, len(q.Options)))
+}
+
+// drawQuestions replaces the questions of the session's finished round with
+// new ones for the next round.
+func (s *QuizService) drawQuestions(session *models.UserSession) error {
+	questions, err := s.repo.GetRandomQuestions(questionsPerRound)
+	if err != nil {
+		return err
+	}
+	session.Questions = make([]uint, len(questions))
+	for i := range questions {
+		session.Questions[i] = questions[i].ID
+	}
+	session.StartTime = time.Now()
+	session.EndTime = nil
+	return nil
+}
+
+func (s *QuizService) saveSession(ctx context.Context, session *models.UserSession) {
+	if err := s.repo.UpdateSession(session); err != nil {
+		logger.FromContext(ctx).Error("save session", "session_id", session.ID, "err", err)
+	}
+}
+
+// countsAsIncorrect reports whether rec is one of the results counted in
+// IncorrectAnswers: a wrong answer or a timeout, but not a skip.
+func countsAsIncorrect(rec models.AnswerRecord) bool {
+	return rec.Reason == "wrong_answer" || rec.Reason == "timeout"
+}
+
+// answerAt returns the result recorded for the question at idx of the round.
+func answerAt(session *models.UserSession, idx int) models.AnswerRecord {
+	for i := len(session.Answers) - 1; i >= 0; i-- {
+		if session.Answers[i].Idx == idx {
+			return session.Answers[i]
+		}
+	}
+	return models.AnswerRecord{}
+}
+
+// findAnswer returns the recorded result for the question req refers to.
+func findAnswer(session *models.UserSession, req models.AnswerRequest) *models.AnswerRecord {
+	for i := range session.Answers {
+		rec := &session.Answers[i]
+		if rec.Idx == req.Idx && req.Id >= 0 && rec.QuestionID == uint(req.Id) {
+			return rec
+		}
+	}
+	return nil
+}
+
+// handleTimeout finalises the round once its deadline passed and otherwise
+// enforces the question timer. It returns the seconds left for the question,
+// never more than are left of the round, and how long it has been shown.
+// While the round is paused its timers stand still.
+func (s *QuizService) handleTimeout(session *models.UserSession) (timedOut bool, timeLimit int, elapsed time.Duration) {
+	now := session.Clock()
+	if roundExpired(session, now) {
+		elapsed = now.Sub(*session.QuestionStartTime)
+		s.expireRound(session, now)
+		return true, 0, elapsed
+	}
+
+	if freeNavigation(session) {
+		timedOut, timeLimit, elapsed = s.handleFreeTimeout(session, now)
+	} else {
+		timedOut, timeLimit, elapsed = s.handleQuestionTimeout(session, now)
+	}
+	if deadline, ok := questionDeadline(session); ok {
+		timeLimit = secondsLeft(deadline, now)
+	}
+	return
+}
+
+// handleQuestionTimeout records a timeout when the current question of a
+// linear round ran past the server time limit at now.
+func (s *QuizService) handleQuestionTimeout(session *models.UserSession, now time.Time) (timedOut bool, timeLimit int, elapsed time.Duration) {
 	timeLimit = models.QuestionTimeLimit
 	if session.QuestionStartTime == nil || !session.HasActiveGame {
 		return
 	}
 
-	elapsed := int(time.Since(*session.QuestionStartTime).Seconds())
-	if elapsed <= models.ServerTimeLimit {
-		timeLimit = models.QuestionTimeLimit - elapsed
+	elapsed = now.Sub(*session.QuestionStartTime)
+	if !overdue(session.QuestionStartTime.Add(models.QuestionTimeLimit*time.Second), now) {
 		return
 	}
 
 	session.IncorrectAnswers++
-	session.TotalTime += elapsed
+	session.Streak = 0
+	addPlayTime(session, elapsed)
+	session.Answers = append(session.Answers, models.AnswerRecord{
+		Idx:        session.CurrentIndex,
+		QuestionID: session.Questions[session.CurrentIndex],
+		Answer:     -1,
+		Reason:     "timeout",
+	})
 	session.CurrentIndex++
 
 	timedOut = true
+	metrics.Answers.WithLabelValues("timeout").Inc()
 
 	s.setCurrentTime(session)
 	return
@@ -95,6 +402,9 @@ func (s *QuizService) handleTimeout(session *models.UserSession) (timedOut bool,
 func (s *QuizService) setCurrentTime(session *models.UserSession) {
 	now := time.Now()
 	if session.CurrentIndex >= len(session.Questions) {
+		if session.HasActiveGame {
+			metrics.RoundsCompleted.Inc()
+		}
 		session.HasActiveGame = false
 		session.QuestionStartTime = nil
 		session.CurrentIndex = 0
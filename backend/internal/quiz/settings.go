@@ -0,0 +1,18 @@
+package quiz
+
+import "time"
+
+// Settings are the server-wide rules a QuizService plays rounds by.
+type Settings struct {
+	Locales  Locales
+	Feedback Feedback
+	// Scoring scores new rounds; ScoringParams configure policies rounds
+	// recorded under another name are scored by.
+	Scoring       ScoringPolicy
+	ScoringParams ScoringParams
+	Lifelines     Lifelines
+	Navigation    Navigation
+	// RoundTimeLimit is how long a round may last; 0 for no limit.
+	RoundTimeLimit time.Duration
+	Pausing        Pausing
+}
@@ -0,0 +1,72 @@
+package quiz
+
+import (
+	mrand "math/rand"
+	"quiz_backend/models"
+	"slices"
+)
+
+// shuffleOptions draws a new option order for every question of the round,
+// so the position of the correct answer differs between players and rounds.
+func (s *QuizService) shuffleOptions(session *models.UserSession) error {
+	questions, err := s.repo.GetQuestionsByIds(session.Questions)
+	if err != nil {
+		return err
+	}
+	counts := make(map[uint]int, len(questions))
+	for _, q := range questions {
+		counts[q.ID] = len(q.Options)
+	}
+
+	session.OptionOrder = make([][]int, len(session.Questions))
+	for i, id := range session.Questions {
+		session.OptionOrder[i] = mrand.Perm(counts[id])
+	}
+	return nil
+}
+
+// optionOrder returns the order the options of the question at idx are shown
+// in: order[i] is the stored index of the i-th shown option. Without a usable
+// stored order (e.g. the question's options were edited mid-round) the
+// options are shown as stored.
+func optionOrder(session *models.UserSession, idx, n int) []int {
+	if idx < len(session.OptionOrder) && len(session.OptionOrder[idx]) == n {
+		return session.OptionOrder[idx]
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// presentOptions returns a copy of q with its options (and their media) in
+// the given order.
+func presentOptions(q *models.Question, order []int) *models.Question {
+	out := *q
+	out.Options = make([]string, len(order))
+	for i, stored := range order {
+		out.Options[i] = q.Options[stored]
+	}
+	if len(q.OptionMedia) == len(q.Options) {
+		out.OptionMedia = make([]string, len(order))
+		for i, stored := range order {
+			out.OptionMedia[i] = q.OptionMedia[stored]
+		}
+	}
+	return &out
+}
+
+// storedIndex maps an option index as the player saw it to the stored one.
+// Indexes out of range (-1 for a client-side timeout) are passed through.
+func storedIndex(order []int, shown int) int {
+	if shown < 0 || shown >= len(order) {
+		return shown
+	}
+	return order[shown]
+}
+
+// shownIndex maps a stored option index to the player's order.
+func shownIndex(order []int, stored int) int {
+	return slices.Index(order, stored)
+}
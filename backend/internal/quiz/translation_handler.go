@@ -0,0 +1,208 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"quiz_backend/models"
+	"quiz_backend/pkg/response"
+	"quiz_backend/pkg/validation"
+	"strconv"
+
+	"golang.org/x/text/language"
+)
+
+// GetTranslations godoc
+// @Summary      List translations of a question
+// @Tags         translations
+// @Produce      json
+// @Param        id  path  int  true  "Question ID"
+// @Success      200 {array}  models.TranslationDTO
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Router       /questions/{id}/translations [get]
+func (h *QuizHandler) GetTranslations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			response.BadRequest(w, "Invalid ID")
+			return
+		}
+
+		q, err := h.repo.GetQuestionById(uint(id))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+		ts, err := h.repo.GetTranslations(q.ID)
+		if err != nil {
+			response.FromError(w, r, err, "Translations")
+			return
+		}
+
+		dtos := make([]models.TranslationDTO, len(ts))
+		for i := range ts {
+			dtos[i] = response.ToTranslationDTO(&ts[i], IsStale(&ts[i], q))
+		}
+		response.OK(w, dtos)
+	}
+}
+
+// PutTranslation godoc
+// @Summary      Create or replace a translation of a question
+// @Description  options must be translated one to one, in the question's order. The question's format applies to the translation too. Without a hint the hint lifeline is unavailable in this locale.
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        id           path  int                        true  "Question ID"
+// @Param        locale       path  string                     true  "BCP 47 language tag"
+// @Param        translation  body  models.TranslationDataDTO  true  "Translated text and options"
+// @Success      200 {object} models.TranslationDTO
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Failure      422 {object} response.Problem
+// @Router       /questions/{id}/translations/{locale} [put]
+func (h *QuizHandler) PutTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			response.BadRequest(w, "Invalid ID")
+			return
+		}
+		locale, ok := h.translationLocale(w, r)
+		if !ok {
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			response.BadRequest(w, "Invalid JSON")
+			return
+		}
+		var req models.TranslationDataDTO
+		if err := decodeStrict(body, &req); err != nil {
+			response.BadRequest(w, "Invalid JSON: "+err.Error())
+			return
+		}
+
+		q, err := h.repo.GetQuestionById(uint(id))
+		if err != nil {
+			response.FromError(w, r, err, "Question")
+			return
+		}
+
+		errs := validation.Struct(req)
+		if len(req.Options) != len(q.Options) {
+			errs = append(errs, response.FieldError{
+				Field:   "options",
+				Code:    "length_mismatch",
+				Message: fmt.Sprintf("must have %d items, one per option of the question", len(q.Options)),
+			})
+		}
+		if errs != nil {
+			response.UnprocessableEntity(w, "Translation is invalid", errs...)
+			return
+		}
+
+		t := &models.QuestionTranslation{
+			QuestionID: q.ID,
+			Locale:     locale,
+			Text:       req.Text,
+			Options:    req.Options,
+			Hint:       req.Hint,
+		}
+		if err := h.repo.SaveTranslation(t); err != nil {
+			response.FromError(w, r, err, "Translation")
+			return
+		}
+		response.OK(w, response.ToTranslationDTO(t, false))
+	}
+}
+
+// DeleteTranslation godoc
+// @Summary      Delete a translation of a question
+// @Tags         translations
+// @Param        id      path  int     true  "Question ID"
+// @Param        locale  path  string  true  "BCP 47 language tag"
+// @Success      204
+// @Failure      400 {object} response.Problem
+// @Failure      404 {object} response.Problem
+// @Router       /questions/{id}/translations/{locale} [delete]
+func (h *QuizHandler) DeleteTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			response.BadRequest(w, "Invalid ID")
+			return
+		}
+		locale, ok := h.translationLocale(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.repo.DeleteTranslation(uint(id), locale); err != nil {
+			response.FromError(w, r, err, "Translation")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// MissingTranslations godoc
+// @Summary      Report missing and outdated translations
+// @Description  Covers the configured locales plus every locale any question is translated into, or only the given locale. A translation is stale when its question changed after it was written.
+// @Tags         translations
+// @Produce      json
+// @Param        locale  query  string  false  "Only report this locale"
+// @Success      200 {object} models.MissingTranslationsDTO
+// @Failure      400 {object} response.Problem
+// @Router       /translations/missing [get]
+func (h *QuizHandler) MissingTranslations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var locales []string
+		if v := r.URL.Query().Get("locale"); v != "" {
+			tag, err := language.Parse(v)
+			if err != nil {
+				response.BadRequest(w, "Invalid query parameters",
+					response.FieldError{Field: "locale", Code: "invalid", Message: "must be a BCP 47 language tag"})
+				return
+			}
+			locales = []string{tag.String()}
+		} else {
+			var err error
+			if locales, err = h.repo.ReportLocales(h.quizService.settings.Locales); err != nil {
+				response.FromError(w, r, err, "Translations")
+				return
+			}
+		}
+
+		report, err := h.repo.MissingTranslations(locales)
+		if err != nil {
+			response.FromError(w, r, err, "Translations")
+			return
+		}
+		response.OK(w, models.MissingTranslationsDTO{
+			DefaultLocale: h.quizService.settings.Locales.Default.String(),
+			Locales:       locales,
+			Questions:     report,
+		})
+	}
+}
+
+// translationLocale reads the {locale} path value in canonical form. The
+// default locale is rejected: the question itself is written in it.
+func (h *QuizHandler) translationLocale(w http.ResponseWriter, r *http.Request) (string, bool) {
+	tag, err := language.Parse(r.PathValue("locale"))
+	if err != nil {
+		response.BadRequest(w, "Invalid locale, expected a BCP 47 language tag")
+		return "", false
+	}
+	if tag == h.quizService.settings.Locales.Default {
+		response.UnprocessableEntity(w, "The question itself is in the default locale "+tag.String())
+		return "", false
+	}
+	return tag.String(), true
+}
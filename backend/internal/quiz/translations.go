@@ -0,0 +1,185 @@
+package quiz
+
+import (
+	"fmt"
+	"quiz_backend/models"
+	"slices"
+
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Locales configures which languages questions are offered in. Question rows
+// are written in Default; Extra are the locales translators are expected to
+// cover and are always part of the missing-translation report.
+type Locales struct {
+	Default language.Tag
+	Extra   []language.Tag
+}
+
+// ParseLocales builds Locales from BCP 47 tags.
+func ParseLocales(def string, extra []string) (Locales, error) {
+	tag, err := language.Parse(def)
+	if err != nil {
+		return Locales{}, fmt.Errorf("default locale %q: %w", def, err)
+	}
+	l := Locales{Default: tag}
+	for _, e := range extra {
+		tag, err := language.Parse(e)
+		if err != nil {
+			return Locales{}, fmt.Errorf("locale %q: %w", e, err)
+		}
+		l.Extra = append(l.Extra, tag)
+	}
+	return l, nil
+}
+
+func (repo *QuizRepository) GetTranslations(questionID uint) ([]models.QuestionTranslation, error) {
+	var ts []models.QuestionTranslation
+	err := repo.Database.DB.Where("question_id = ?", questionID).Order("locale").Find(&ts).Error
+	return ts, err
+}
+
+// SaveTranslation creates or replaces the translation of t.QuestionID into
+// t.Locale.
+func (repo *QuizRepository) SaveTranslation(t *models.QuestionTranslation) error {
+	return repo.Database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "question_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "options", "hint", "updated_at"}),
+	}).Create(t).Error
+}
+
+func (repo *QuizRepository) DeleteTranslation(questionID uint, locale string) error {
+	res := repo.Database.DB.Where("question_id = ? AND locale = ?", questionID, locale).Delete(&models.QuestionTranslation{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TranslationLocales returns every locale at least one question is
+// translated into.
+func (repo *QuizRepository) TranslationLocales() ([]string, error) {
+	var locales []string
+	err := repo.Database.DB.Model(&models.QuestionTranslation{}).Distinct().Order("locale").Pluck("locale", &locales).Error
+	return locales, err
+}
+
+// MissingTranslations reports, for every question, which of locales it lacks
+// a translation for or has only a stale one.
+func (repo *QuizRepository) MissingTranslations(locales []string) ([]models.QuestionTranslationStatus, error) {
+	questions, err := repo.AllQuestions()
+	if err != nil {
+		return nil, err
+	}
+
+	var ts []models.QuestionTranslation
+	if err := repo.Database.DB.Where("locale IN ?", locales).Find(&ts).Error; err != nil {
+		return nil, err
+	}
+	byQuestion := make(map[uint]map[string]*models.QuestionTranslation)
+	for i := range ts {
+		t := &ts[i]
+		if byQuestion[t.QuestionID] == nil {
+			byQuestion[t.QuestionID] = make(map[string]*models.QuestionTranslation)
+		}
+		byQuestion[t.QuestionID][t.Locale] = t
+	}
+
+	report := []models.QuestionTranslationStatus{}
+	for i := range questions {
+		q := &questions[i]
+		status := models.QuestionTranslationStatus{ID: q.ID, Text: q.Text}
+		for _, locale := range locales {
+			t, ok := byQuestion[q.ID][locale]
+			switch {
+			case !ok:
+				status.Missing = append(status.Missing, locale)
+			case IsStale(t, q):
+				status.Stale = append(status.Stale, locale)
+			}
+		}
+		if status.Missing != nil || status.Stale != nil {
+			report = append(report, status)
+		}
+	}
+	return report, nil
+}
+
+// IsStale reports whether q was edited after t was written or no longer has
+// as many options as t.
+func IsStale(t *models.QuestionTranslation, q *models.Question) bool {
+	return t.UpdatedAt.Before(q.UpdatedAt) || len(t.Options) != len(q.Options)
+}
+
+// ReportLocales are the locales the missing-translation report covers: the
+// configured ones plus any that translations exist for.
+func (repo *QuizRepository) ReportLocales(l Locales) ([]string, error) {
+	locales, err := repo.TranslationLocales()
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range l.Extra {
+		locales = append(locales, tag.String())
+	}
+	slices.Sort(locales)
+	locales = slices.Compact(locales)
+	return slices.DeleteFunc(locales, func(s string) bool { return s == l.Default.String() }), nil
+}
+
+// localize returns q in the locale that best matches prefs, falling back to
+// the default locale. Stale translations are skipped: the question may have
+// been edited since, and its options or correct answer may no longer match.
+func localize(repo *QuizRepository, l Locales, q *models.Question, prefs []language.Tag) *models.Question {
+	out := *q
+	out.Locale = l.Default.String()
+	if len(prefs) == 0 {
+		return &out
+	}
+
+	ts, err := repo.GetTranslations(q.ID)
+	if err != nil || len(ts) == 0 {
+		return &out
+	}
+
+	supported := []language.Tag{l.Default}
+	usable := []*models.QuestionTranslation{nil}
+	for i := range ts {
+		if IsStale(&ts[i], q) {
+			continue
+		}
+		tag, err := language.Parse(ts[i].Locale)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		usable = append(usable, &ts[i])
+	}
+
+	_, idx, conf := language.NewMatcher(supported).Match(prefs...)
+	if conf == language.No || usable[idx] == nil {
+		return &out
+	}
+	out.Text = usable[idx].Text
+	out.Options = usable[idx].Options
+	out.Hint = usable[idx].Hint
+	out.Locale = usable[idx].Locale
+	return &out
+}
+
+// localePrefs puts the session's chosen locale ahead of the request's
+// Accept-Language preferences.
+func localePrefs(session *models.UserSession, accept []language.Tag) []language.Tag {
+	if session.Locale == "" {
+		return accept
+	}
+	tag, err := language.Parse(session.Locale)
+	if err != nil {
+		return accept
+	}
+	return append([]language.Tag{tag}, accept...)
+}
@@ -3,14 +3,21 @@ package session
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"net/http"
 	"quiz_backend/models"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/metrics"
 	"time"
 )
 
+const CookieName = "quiz_session"
+
+// QuestionsPerRound is how many questions a round is drawn with.
+const QuestionsPerRound = 10
+
 type SessionRepository interface {
 	CreateSession(session *models.UserSession) error
-	GetActiveSessionByToken(token string) (*models.UserSession, error)
 	GetSessionByToken(token string) (*models.UserSession, error)
 	UpdateSession(s *models.UserSession) error
 	GetRandomQuestions(n int) ([]models.Question, error)
@@ -24,11 +31,16 @@ func NewService(repo SessionRepository) *Service {
 	return &Service{repo: repo}
 }
 
+// GetOrCreateSession returns the session of the request's cookie or starts a
+// new one. A session whose round is over is kept, so the round can still be
+// reviewed until the next one starts on it.
 func (s *Service) GetOrCreateSession(w http.ResponseWriter, r *http.Request) (*models.UserSession, error) {
-	if cookie, err := r.Cookie("quiz_session"); err == nil {
-		if sess, err := s.repo.GetActiveSessionByToken(cookie.Value); err == nil {
+	if cookie, err := r.Cookie(CookieName); err == nil {
+		sess, err := s.repo.GetSessionByToken(cookie.Value)
+		if err == nil {
 			return sess, nil
 		}
+		logger.FromContext(r.Context()).Debug("no session for cookie, creating a new one", "err", err)
 		s.clearSessionCookie(w)
 	}
 
@@ -36,18 +48,27 @@ func (s *Service) GetOrCreateSession(w http.ResponseWriter, r *http.Request) (*m
 }
 
 func (s *Service) GetSession(r *http.Request) (*models.UserSession, error) {
-	cookie, err := r.Cookie("quiz_session")
+	token, err := s.Token(r)
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.GetSessionByToken(cookie.Value)
+	return s.repo.GetSessionByToken(token)
+}
+
+// Token returns the session token carried by the request cookie.
+func (s *Service) Token(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
 }
 
 func (s *Service) createNewSession(w http.ResponseWriter) (*models.UserSession, error) {
 	token := generateSessionToken()
-	questions, err := s.repo.GetRandomQuestions(10)
+	questions, err := s.repo.GetRandomQuestions(QuestionsPerRound)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("pick questions: %w", err)
 	}
 	var qids []uint
 	for _, q := range questions {
@@ -61,8 +82,9 @@ func (s *Service) createNewSession(w http.ResponseWriter) (*models.UserSession,
 	}
 	err = s.repo.CreateSession(session)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("create session: %w", err)
 	}
+	metrics.SessionsCreated.Inc()
 
 	s.setSessionCookie(w, session.SessionToken)
 	return session, nil
@@ -70,7 +92,7 @@ func (s *Service) createNewSession(w http.ResponseWriter) (*models.UserSession,
 
 func (s *Service) setSessionCookie(w http.ResponseWriter, token string) {
 	http.SetCookie(w, &http.Cookie{
-		Name:     "quiz_session",
+		Name:     CookieName,
 		Value:    token,
 		Path:     "/",
 		HttpOnly: true,
@@ -82,7 +104,7 @@ func (s *Service) setSessionCookie(w http.ResponseWriter, token string) {
 
 func (s *Service) clearSessionCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
-		Name:     "quiz_session",
+		Name:     CookieName,
 		Value:    "",
 		Path:     "/",
 		HttpOnly: true,
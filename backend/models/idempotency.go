@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a mutating request sent with an
+// Idempotency-Key so that retries get the same response.
+type IdempotencyRecord struct {
+	ID          uint   `gorm:"primarykey"`
+	Key         string `gorm:"uniqueIndex;size:320"` // caller scope, then the client's key
+	Fingerprint string `gorm:"size:64"`              // sha256 of method, path and body
+	Completed   bool   // false while the first request is still in flight
+	Status      int
+	Header      map[string]string `gorm:"serializer:json"`
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
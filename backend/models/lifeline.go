@@ -0,0 +1,34 @@
+package models
+
+// Lifeline kinds a player can use on the current question.
+const (
+	LifelineHint       = "hint"        // shows the question's hint
+	LifelineFiftyFifty = "fifty_fifty" // removes two wrong options
+	LifelineSkip       = "skip"        // moves on without answering
+)
+
+// LifelineRecord is a lifeline used in a round. Removed holds the stored
+// indexes of the options a 50/50 took away.
+type LifelineRecord struct {
+	Idx        int    `json:"question_idx"`
+	QuestionID uint   `json:"question_id"`
+	Kind       string `json:"kind"`
+	Removed    []int  `json:"removed,omitempty"`
+}
+
+type LifelineRequest struct {
+	Lifeline string `json:"lifeline" validate:"required,oneof=hint fifty_fifty skip"`
+	Idx      int    `json:"question_idx"`
+	Id       int    `json:"question_id"`
+}
+
+// LifelineResponse is the effect of a lifeline. Using hint or fifty_fifty
+// again on the same question repeats the effect without using up another one.
+type LifelineResponse struct {
+	Lifeline       string         `json:"lifeline"`
+	HintHTML       string         `json:"hint_html,omitempty"`       // hint: sanitised HTML
+	RemovedOptions []int          `json:"removed_options,omitempty"` // fifty_fifty: indexes in the player's order
+	LifelinesLeft  map[string]int `json:"lifelines_left"`
+	SessionStats
+	NextQuestion *QuestionDTO `json:"next_question,omitempty"` // skip: the question after the skipped one
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Media is an uploaded image or audio file. The file itself lives in the
+// media storage under Key; questions and options refer to it by that key.
+type Media struct {
+	ID          uint   `gorm:"primarykey"`
+	Key         string `gorm:"uniqueIndex;size:64"`
+	ContentType string `gorm:"size:100"`
+	Size        int64
+	SHA256      string `gorm:"size:64"` // hex digest of the content, served as ETag
+	CreatedAt   time.Time
+}
+
+type MediaDTO struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// Navigation modes of a round.
+const (
+	NavigationLinear = "linear" // one question after the other, answers are final
+	NavigationFree   = "free"   // jump between questions, answers are drafts until finished
+)
+
+// Timers of a free-navigation round.
+const (
+	TimerQuestion = "question" // every question has its own time limit
+	TimerRound    = "round"    // one deadline for the whole round
+)
+
+type NavigateRequest struct {
+	Idx int `json:"question_idx"`
+}
+
+type FlagRequest struct {
+	Idx     int  `json:"question_idx"`
+	Flagged bool `json:"flagged"`
+}
+
+// NavigationDTO is the state of every question of a free-navigation round.
+type NavigationDTO struct {
+	Mode      string              `json:"mode"`
+	Timer     string              `json:"timer"`
+	Questions []NavigationItemDTO `json:"questions"`
+}
+
+type NavigationItemDTO struct {
+	Idx      int  `json:"question_idx"`
+	Answer   int  `json:"answer"` // draft in the player's option order, -1 for none
+	Answered bool `json:"answered"`
+	Flagged  bool `json:"flagged"`
+	Locked   bool `json:"locked"`    // its time ran out, the draft is final
+	TimeLeft int  `json:"time_left"` // seconds, for the question timer
+}
@@ -15,50 +15,172 @@ const ServerTimeLimit = QuestionTimeLimit + TimeGracePeriod
 
 type Question struct {
 	gorm.Model
-	Text          string   `json:"text"`
-	Options       []string `json:"options" gorm:"serializer:json"`
-	CorrectAnswer int      `json:"correct_answer"`
+	Text          string      `json:"text"`
+	Format        string      `json:"format" gorm:"size:16;not null;default:plain"` // plain or markdown, applies to text and options
+	Options       []string    `json:"options" gorm:"serializer:json"`
+	CorrectAnswer int         `json:"correct_answer"`
+	Media         string      `json:"media" gorm:"size:64"`                // media key, "" for none
+	OptionMedia   []string    `json:"option_media" gorm:"serializer:json"` // media key per option, "" for none
+	Hint          string      `json:"hint"`                                // markdown, shown by the hint lifeline
+	Weight        int         `json:"weight" gorm:"not null;default:1"`    // multiplies the points of a correct answer
+	Explanation   string      `json:"explanation"`                         // markdown, shown with the answer's result
+	References    []Reference `json:"references" gorm:"serializer:json"`   // further reading on the answer
+	Version       uint        `json:"-" gorm:"not null;default:1"`         // bumped on every update, exposed as ETag
+	// Locale of Text and Options when they were replaced by a translation for
+	// a player; not stored.
+	Locale string `json:"-" gorm:"-"`
 }
 
 type QuestionDataDTO struct {
-	Text          string   `json:"text"`
-	Options       []string `json:"options" gorm:"serializer:json"`
-	CorrectAnswer int      `json:"correct_answer"`
+	Text          string      `json:"text" validate:"required,max=1000"`
+	Format        string      `json:"format,omitempty" validate:"oneof=plain markdown"`
+	Options       []string    `json:"options" gorm:"serializer:json" validate:"required,min=2,max=10,unique,dive,required,max=300"`
+	CorrectAnswer int         `json:"correct_answer" validate:"index=Options"`
+	Media         string      `json:"media,omitempty" validate:"max=64"`
+	OptionMedia   []string    `json:"option_media,omitempty" validate:"parallel=Options,dive,max=64"`
+	Hint          string      `json:"hint,omitempty" validate:"max=1000"`
+	Weight        int         `json:"weight,omitempty" validate:"min=0,max=10"` // 0 means 1
+	Explanation   string      `json:"explanation,omitempty" validate:"max=5000"`
+	References    []Reference `json:"references,omitempty" validate:"max=10,dive"`
+}
+
+// Reference is a link players can follow to learn more about an answer.
+type Reference struct {
+	Title string `json:"title,omitempty" validate:"max=200"`
+	URL   string `json:"url" validate:"required,url,max=2000"`
 }
 
 type AdminPanelQuestionDTO struct {
-	ID            uint     `json:"id"`
-	Text          string   `json:"text"`
-	Options       []string `json:"options"`
-	CorrectAnswer int      `json:"correct_answer"`
+	ID            uint        `json:"id"`
+	Text          string      `json:"text"`
+	Format        string      `json:"format"`
+	Options       []string    `json:"options"`
+	CorrectAnswer int         `json:"correct_answer"`
+	Media         string      `json:"media,omitempty"`
+	OptionMedia   []string    `json:"option_media,omitempty"`
+	Hint          string      `json:"hint,omitempty"`
+	Weight        int         `json:"weight"`
+	Explanation   string      `json:"explanation,omitempty"`
+	References    []Reference `json:"references,omitempty"`
+	Version       uint        `json:"version"`
+	// Set in search results: matched fragments as HTML with <mark> tags.
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+	// Set on create: existing questions the new one closely resembles.
+	PossibleDuplicates []DuplicateMatchDTO `json:"possible_duplicates,omitempty"`
+}
+
+// DuplicateMatchDTO is an existing question scored against another one.
+// Scores run from 0 (unrelated) to 1 (identical).
+type DuplicateMatchDTO struct {
+	ID          uint    `json:"id"`
+	Text        string  `json:"text"`
+	Score       float64 `json:"score"`
+	TextScore   float64 `json:"text_score"`
+	OptionScore float64 `json:"option_score"`
+}
+
+type DuplicateReportEntryDTO struct {
+	ID      uint                `json:"id"`
+	Text    string              `json:"text"`
+	Matches []DuplicateMatchDTO `json:"matches"`
+}
+
+type DuplicateReportDTO struct {
+	Threshold float64                   `json:"threshold"`
+	Questions []DuplicateReportEntryDTO `json:"questions"`
+}
+
+type SearchHighlight struct {
+	Text    string `json:"text,omitempty"`
+	Options string `json:"options,omitempty"`
 }
 
 type AdminPanelQuestionsDTO struct {
-	Questions []AdminPanelQuestionDTO `json:"questions"`
-	Pages     int64                   `json:"total_pages"`
-	Total     int64                   `json:"total_count"`
-	Page      int                     `json:"page"`
+	Questions  []AdminPanelQuestionDTO `json:"questions"`
+	Pages      int64                   `json:"total_pages"`
+	Total      int64                   `json:"total_count"`
+	Page       int                     `json:"page"`                  // 0 when paging by cursor
+	NextCursor string                  `json:"next_cursor,omitempty"` // pass as ?cursor= for the following page
+	PrevCursor string                  `json:"prev_cursor,omitempty"` // pass as ?cursor= for the preceding page
 }
 
 type QuestionDTO struct {
-	ID        uint     `json:"id"`
-	Text      string   `json:"text"`
-	Options   []string `json:"options"`
-	TimeLimit int      `json:"time_limit"`
+	ID              uint       `json:"id"`
+	Text            string     `json:"text"`
+	Options         []string   `json:"options"`
+	Locale          string     `json:"locale"`       // locale of Text and Options
+	Format          string     `json:"format"`       // format of Text and Options
+	TextHTML        string     `json:"text_html"`    // Text rendered to sanitised HTML
+	OptionsHTML     []string   `json:"options_html"` // Options rendered to sanitised inline HTML
+	MediaURL        string     `json:"media_url,omitempty"`
+	OptionMediaURLs []string   `json:"option_media_urls,omitempty"` // "" for options without media
+	TimeLimit       int        `json:"time_limit"`                  // seconds until Deadline
+	Deadline        *time.Time `json:"deadline,omitempty"`          // when the answer is due; answers more than TimeGracePeriod later time out
 }
 
 type UserSession struct {
 	gorm.Model
-	SessionToken      string     `json:"session_token" gorm:"uniqueIndex"`
-	StartTime         time.Time  `json:"start_time"`
-	EndTime           *time.Time `json:"end_time,omitempty"`
-	CorrectAnswers    int        `json:"correct_answers"`
-	IncorrectAnswers  int        `json:"incorrect_answers"`
-	TotalTime         int        `json:"total_time"`                       // in seconds
-	Questions         []uint     `json:"questions" gorm:"serializer:json"` // IDs of 10 questions for the round
-	CurrentIndex      int        `json:"current_index"`                    // index in Questions slice (0-9)
-	HasActiveGame     bool       `json:"has_active_game"`
-	QuestionStartTime *time.Time `json:"question_start_time,omitempty"` // when current question was issued
+	SessionToken      string           `json:"session_token" gorm:"uniqueIndex"`
+	StartTime         time.Time        `json:"start_time"`
+	EndTime           *time.Time       `json:"end_time,omitempty"`
+	CorrectAnswers    int              `json:"correct_answers"`
+	IncorrectAnswers  int              `json:"incorrect_answers"`
+	Score             int              `json:"score"`                            // points of the current or last round
+	Streak            int              `json:"streak"`                           // correct answers in a row
+	TotalTime         int              `json:"total_time"`                       // seconds played, pauses excluded
+	TotalTimeMs       int64            `json:"total_time_ms"`                    // TotalTime to the millisecond
+	Questions         []uint           `json:"questions" gorm:"serializer:json"` // IDs of 10 questions for the round
+	CurrentIndex      int              `json:"current_index"`                    // index in Questions slice (0-9)
+	HasActiveGame     bool             `json:"has_active_game"`
+	QuestionStartTime *time.Time       `json:"question_start_time,omitempty"`       // when current question was issued
+	Answers           []AnswerRecord   `json:"answers" gorm:"serializer:json"`      // results of the current round
+	Lifelines         []LifelineRecord `json:"lifelines" gorm:"serializer:json"`    // lifelines used in the current round
+	NavigationMode    string           `json:"navigation_mode" gorm:"size:16"`      // linear or free, fixed at round start
+	NavigationTimer   string           `json:"navigation_timer" gorm:"size:16"`     // question or round, for free navigation
+	Drafts            []int            `json:"drafts" gorm:"serializer:json"`       // free navigation: answer per question in the player's order, -1 for none
+	Flags             []bool           `json:"flags" gorm:"serializer:json"`        // free navigation: questions flagged for review
+	Spent             []int            `json:"spent" gorm:"serializer:json"`        // free navigation: milliseconds each question was shown
+	RoundDeadline     *time.Time       `json:"round_deadline,omitempty"`            // when the round timer runs out
+	PausedAt          *time.Time       `json:"paused_at,omitempty"`                 // set while the round is paused
+	Pauses            int              `json:"pauses"`                              // pauses taken in the current round
+	OptionOrder       [][]int          `json:"option_order" gorm:"serializer:json"` // per question of the round, stored option index of each shown position
+	Locale            string           `json:"locale,omitempty" gorm:"size:35"`     // chosen by the player, overrides Accept-Language
+	FeedbackPolicy    string           `json:"feedback_policy" gorm:"size:32"`      // when results are revealed, fixed at round start
+	ScoringPolicy     string           `json:"scoring_policy" gorm:"size:32"`       // name of the policy the round is scored by
+	FeedbackCloseAt   *time.Time       `json:"feedback_close_at,omitempty"`         // for the after_close_date policy
+	Version           uint             `json:"-" gorm:"not null;default:1"`         // optimistic lock, bumped on every save
+}
+
+// AnswerRecord is the stored result of one question in a round. It lets a
+// retried submission receive the original outcome instead of being re-scored.
+type AnswerRecord struct {
+	Idx        int            `json:"question_idx"`
+	QuestionID uint           `json:"question_id"`
+	Answer     int            `json:"answer"` // as submitted, in the player's option order
+	Correct    bool           `json:"correct"`
+	Reason     string         `json:"reason"`
+	Score      ScoreBreakdown `json:"score"`
+}
+
+// ScoreBreakdown is how the points of one answer came about.
+type ScoreBreakdown struct {
+	Base        int     `json:"base"`              // base points times question weight
+	SpeedBonus  int     `json:"speed_bonus"`       // for answering early
+	Multiplier  float64 `json:"streak_multiplier"` // applied to base and speed bonus
+	StreakBonus int     `json:"streak_bonus"`      // what the multiplier added
+	Penalty     int     `json:"penalty"`           // negative marking for a wrong answer
+	Points      int     `json:"points"`            // Base + SpeedBonus + StreakBonus - Penalty
+}
+
+// ScoreDTO is the score of a round with the sums of its components.
+type ScoreDTO struct {
+	Policy      string `json:"policy"` // scoring policy the points were given by
+	Total       int    `json:"total"`
+	Base        int    `json:"base"`
+	SpeedBonus  int    `json:"speed_bonus"`
+	StreakBonus int    `json:"streak_bonus"`
+	Penalty     int    `json:"penalty"`
+	Streak      int    `json:"streak"` // current run of correct answers
 }
 
 type AnswerRequest struct {
@@ -67,24 +189,79 @@ type AnswerRequest struct {
 	Id     int `json:"question_id"`
 }
 
+// AnswerResponse is the outcome of a submitted answer. Under a feedback
+// policy that withholds results, Correct, Answer, the explanation and the
+// points are omitted, Reason is "recorded" (or "timeout") and FeedbackWithheld is set.
 type AnswerResponse struct {
-	Correct bool   `json:"correct"`
-	Answer  int    `json:"correct_answer_idx"`
-	Reason  string `json:"reason,omitempty"` // "timeout", "wrong_answer", "correct"
+	Correct          *bool           `json:"correct,omitempty"`
+	Answer           *int            `json:"correct_answer_idx,omitempty"`
+	Reason           string          `json:"reason,omitempty"` // "timeout", "wrong_answer", "correct", "recorded"
+	FeedbackWithheld bool            `json:"feedback_withheld,omitempty"`
+	ExplanationHTML  string          `json:"explanation_html,omitempty"` // why the correct answer is correct, sanitised HTML
+	References       []Reference     `json:"references,omitempty"`
+	Points           *ScoreBreakdown `json:"points,omitempty"` // what this answer scored
 	SessionStats
 	NextQuestion *QuestionDTO `json:"next_question,omitempty"`
 }
 
+// StartResponse is the state of the current round. NextQuestion is omitted
+// once the round is over and while it is paused.
 type StartResponse struct {
 	SessionStats
-	NextQuestion *QuestionDTO `json:"next_question,omitempty"`
+	LifelinesLeft map[string]int `json:"lifelines_left"`
+	PausesLeft    int            `json:"pauses_left"`
+	NextQuestion  *QuestionDTO   `json:"next_question,omitempty"`
 }
 
+// SessionStats describes the progress of a round. The totals and the score
+// are omitted while the feedback policy withholds results.
 type SessionStats struct {
-	CurrentIndex   int  `json:"current_index"`
-	HasActiveGame  bool `json:"has_active_game"`
-	TotalCorrect   int  `json:"total_correct"`
-	TotalIncorrect int  `json:"total_incorrect"`
+	CurrentIndex   int       `json:"current_index"`
+	HasActiveGame  bool      `json:"has_active_game"`
+	TotalCorrect   *int      `json:"total_correct,omitempty"`
+	TotalIncorrect *int      `json:"total_incorrect,omitempty"`
+	Score          *ScoreDTO `json:"score,omitempty"`
+	Paused         bool      `json:"paused,omitempty"`
+	// When the response was made, to compare deadlines with the client clock.
+	ServerTime time.Time `json:"server_time"`
+	// Seconds left until the round deadline, for rounds with one. The
+	// deadline itself is omitted while the round is paused.
+	RoundTimeLeft *int       `json:"round_time_left,omitempty"`
+	RoundDeadline *time.Time `json:"round_deadline,omitempty"`
+	// Set in free-navigation rounds.
+	Navigation *NavigationDTO `json:"navigation,omitempty"`
+}
+
+// ServerTimeDTO is the server clock, for clients to estimate their offset.
+type ServerTimeDTO struct {
+	ServerTime time.Time `json:"server_time"`
+	UnixMs     int64     `json:"unix_ms"`
+}
+
+// ReviewDTO is the breakdown of a player's latest round.
+type ReviewDTO struct {
+	FeedbackPolicy string          `json:"feedback_policy"`
+	HasActiveGame  bool            `json:"has_active_game"`
+	TotalCorrect   int             `json:"total_correct"`
+	TotalIncorrect int             `json:"total_incorrect"`
+	Score          ScoreDTO        `json:"score"`
+	Answers        []ReviewItemDTO `json:"answers"`
+}
+
+// ReviewItemDTO is one answered question of a round. Options and indexes are
+// in the order the player saw them; Answer is -1 when no option was chosen.
+type ReviewItemDTO struct {
+	Idx             int            `json:"question_idx"`
+	QuestionID      uint           `json:"question_id"`
+	Text            string         `json:"text"`
+	Options         []string       `json:"options"`
+	Answer          int            `json:"answer"`
+	CorrectAnswer   int            `json:"correct_answer_idx"`
+	Correct         bool           `json:"correct"`
+	Reason          string         `json:"reason"`
+	ExplanationHTML string         `json:"explanation_html,omitempty"`
+	References      []Reference    `json:"references,omitempty"`
+	Points          ScoreBreakdown `json:"points"`
 }
 
 func (q Question) OptionsValue() (driver.Value, error) {
@@ -99,6 +276,15 @@ func (q *Question) OptionsScan(value any) error {
 	return json.Unmarshal(b, &q.Options)
 }
 
+// Clock returns the time the session's timers stand at: the moment the round
+// was paused while it is, else now.
+func (s *UserSession) Clock() time.Time {
+	if s.PausedAt != nil {
+		return *s.PausedAt
+	}
+	return time.Now()
+}
+
 func (s UserSession) QuestionsValue() (driver.Value, error) {
 	return json.Marshal(s.Questions)
 }
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// QuestionTranslation holds a question's text, options and hint in another
+// locale. Options are in the same order as in the question, so the correct
+// answer index applies unchanged.
+type QuestionTranslation struct {
+	ID         uint     `gorm:"primarykey"`
+	QuestionID uint     `gorm:"uniqueIndex:idx_translation_question_locale;not null"`
+	Locale     string   `gorm:"uniqueIndex:idx_translation_question_locale;size:35;not null"` // BCP 47 tag
+	Text       string   `gorm:"not null"`
+	Options    []string `gorm:"serializer:json"`
+	Hint       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type TranslationDataDTO struct {
+	Text    string   `json:"text" validate:"required,max=1000"`
+	Options []string `json:"options" validate:"required,dive,required,max=300"`
+	Hint    string   `json:"hint,omitempty" validate:"max=1000"`
+}
+
+type TranslationDTO struct {
+	Locale    string    `json:"locale"`
+	Text      string    `json:"text"`
+	Options   []string  `json:"options"`
+	Hint      string    `json:"hint,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Stale is set when the question changed after it was translated.
+	Stale bool `json:"stale"`
+}
+
+type MissingTranslationsDTO struct {
+	DefaultLocale string                      `json:"default_locale"`
+	Locales       []string                    `json:"locales"`
+	Questions     []QuestionTranslationStatus `json:"questions"`
+}
+
+// QuestionTranslationStatus lists the locales a question has no translation
+// for and those whose translation predates the last change of the question.
+type QuestionTranslationStatus struct {
+	ID      uint     `json:"id"`
+	Text    string   `json:"text"`
+	Missing []string `json:"missing,omitempty"`
+	Stale   []string `json:"stale,omitempty"`
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime settings read from the environment (and .env).
+type Config struct {
+	// IdempotencyTTL is how long responses to requests carrying an
+	// Idempotency-Key are kept for replay. IDEMPOTENCY_TTL, default 24h.
+	IdempotencyTTL time.Duration
+
+	// DuplicateThreshold is the similarity score (0-1) from which a new
+	// question counts as a near-duplicate. DUPLICATE_THRESHOLD, default 0.6.
+	DuplicateThreshold float64
+	// RejectDuplicates makes creating a near-duplicate fail with 409 instead
+	// of returning a warning. DUPLICATE_MODE=reject|warn, default warn.
+	RejectDuplicates bool
+
+	// MediaDir is where uploaded media files are stored. MEDIA_DIR, default
+	// "media".
+	MediaDir string
+	// MediaMaxSize is the largest accepted upload in bytes. MEDIA_MAX_SIZE,
+	// default 5 MiB.
+	MediaMaxSize int64
+
+	// DefaultLocale is the language questions are written in. DEFAULT_LOCALE,
+	// default "en".
+	DefaultLocale string
+	// Locales lists further languages questions should be translated into.
+	// LOCALES, comma-separated, default none.
+	Locales []string
+
+	// FeedbackPolicy decides when players see whether they answered
+	// correctly: immediate, end_of_round, never or after_close_date.
+	// FEEDBACK_POLICY, default "immediate".
+	FeedbackPolicy string
+	// FeedbackCloseDate is the RFC 3339 time from which results are shown
+	// under the after_close_date policy. FEEDBACK_CLOSE_DATE.
+	FeedbackCloseDate string
+
+	// ScoringPolicy names the rules rounds are scored by: flat,
+	// speed_weighted, kahoot or partial_credit. SCORING_POLICY, default
+	// "speed_weighted".
+	ScoringPolicy string
+	// ScoreBasePoints is what a correct answer to a question of weight 1 is
+	// worth. SCORE_BASE_POINTS, default 100.
+	ScoreBasePoints int
+	// ScoreSpeedBonus is the most a correct answer can earn on top for speed,
+	// shrinking linearly to 0 over the time limit. SCORE_SPEED_BONUS,
+	// default 50.
+	ScoreSpeedBonus int
+	// ScoreStreakStep raises the multiplier by this much for every further
+	// correct answer in a row. SCORE_STREAK_STEP, default 0.1.
+	ScoreStreakStep float64
+	// ScoreStreakMax caps the streak multiplier. SCORE_STREAK_MAX, default 2.
+	ScoreStreakMax float64
+	// ScoreWrongPenalty is subtracted per weight for a wrong answer; 0 turns
+	// negative marking off. SCORE_WRONG_PENALTY, default 0.
+	ScoreWrongPenalty int
+
+	// LifelineHints, LifelineFiftyFifty and LifelineSkips limit how many
+	// hints, 50/50s and skips a round allows. LIFELINE_HINTS, default 3;
+	// LIFELINE_FIFTY_FIFTY, default 1; LIFELINE_SKIPS, default 1.
+	LifelineHints      int
+	LifelineFiftyFifty int
+	LifelineSkips      int
+	// HintPenalty and FiftyFiftyPenalty are the shares of a correct answer's
+	// points a hint and a 50/50 on the question cost. HINT_PENALTY, default
+	// 0.25; FIFTY_FIFTY_PENALTY, default 0.5.
+	HintPenalty       float64
+	FiftyFiftyPenalty float64
+
+	// NavigationMode is linear, answering questions one after another, or
+	// free, jumping between them before submitting the round.
+	// NAVIGATION_MODE, default "linear".
+	NavigationMode string
+	// NavigationTimer times free rounds per question or for the whole round.
+	// NAVIGATION_TIMER, default "question".
+	NavigationTimer string
+	// RoundTimeLimit is how long a whole round may last, e.g. 15m; unanswered
+	// questions time out once it is over. ROUND_TIME_LIMIT, default none.
+	RoundTimeLimit time.Duration
+	// PausesPerRound is how often a player may pause a round. Pausing is
+	// opt-in: the default 0 turns it off. PAUSES_PER_ROUND, default 0.
+	PausesPerRound int
+}
+
+func Load() Config {
+	return Config{
+		IdempotencyTTL:     envDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		DuplicateThreshold: envFloat("DUPLICATE_THRESHOLD", 0.6, 0, 1),
+		RejectDuplicates:   envString("DUPLICATE_MODE", "warn") == "reject",
+		MediaDir:           envString("MEDIA_DIR", "media"),
+		MediaMaxSize:       envInt64("MEDIA_MAX_SIZE", 5<<20),
+		DefaultLocale:      envString("DEFAULT_LOCALE", "en"),
+		Locales:            envList("LOCALES"),
+		FeedbackPolicy:     envString("FEEDBACK_POLICY", "immediate"),
+		FeedbackCloseDate:  envString("FEEDBACK_CLOSE_DATE", ""),
+		ScoringPolicy:      envString("SCORING_POLICY", "speed_weighted"),
+		ScoreBasePoints:    envInt("SCORE_BASE_POINTS", 100, 1, 10000),
+		ScoreSpeedBonus:    envInt("SCORE_SPEED_BONUS", 50, 0, 10000),
+		ScoreStreakStep:    envFloat("SCORE_STREAK_STEP", 0.1, 0, 1),
+		ScoreStreakMax:     envFloat("SCORE_STREAK_MAX", 2, 1, 10),
+		ScoreWrongPenalty:  envInt("SCORE_WRONG_PENALTY", 0, 0, 10000),
+		LifelineHints:      envInt("LIFELINE_HINTS", 3, 0, 100),
+		LifelineFiftyFifty: envInt("LIFELINE_FIFTY_FIFTY", 1, 0, 100),
+		LifelineSkips:      envInt("LIFELINE_SKIPS", 1, 0, 100),
+		HintPenalty:        envFloat("HINT_PENALTY", 0.25, 0, 1),
+		FiftyFiftyPenalty:  envFloat("FIFTY_FIFTY_PENALTY", 0.5, 0, 1),
+		NavigationMode:     envString("NAVIGATION_MODE", "linear"),
+		NavigationTimer:    envString("NAVIGATION_TIMER", "question"),
+		RoundTimeLimit:     envDuration("ROUND_TIME_LIMIT", 0),
+		PausesPerRound:     envInt("PAUSES_PER_ROUND", 0, 0, 100),
+	}
+}
+
+func envString(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func envList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(envString(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := envString(key, "")
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("invalid duration in environment, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return d
+}
+
+func envFloat(key string, def, lo, hi float64) float64 {
+	v := envString(key, "")
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < lo || f > hi {
+		slog.Warn("invalid number in environment, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return f
+}
+
+func envInt(key string, def, lo, hi int) int {
+	v := envString(key, "")
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < lo || n > hi {
+		slog.Warn("invalid number in environment, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return n
+}
+
+func envInt64(key string, def int64) int64 {
+	v := envString(key, "")
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		slog.Warn("invalid number in environment, using default", "key", key, "value", v, "default", def)
+		return def
+	}
+	return n
+}
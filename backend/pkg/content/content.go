@@ -0,0 +1,69 @@
+// Package content renders question text for players. Markdown is converted
+// to HTML with highlighted code blocks and everything is passed through an
+// allowlist sanitiser, so nothing an admin types reaches players as raw HTML.
+package content
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Formats of question source text.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		highlighting.NewHighlighting(
+			highlighting.WithStyle("github"),
+			highlighting.WithFormatOptions(chromahtml.TabWidth(4)),
+		),
+	),
+)
+
+// policy allows user-generated markup plus the inline colours the highlighter
+// emits on code blocks.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").
+		OnElements("span", "pre")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}()
+
+// Render returns src as sanitised HTML. Plain text is escaped and keeps its
+// line breaks.
+func Render(format, src string) string {
+	if format != FormatMarkdown {
+		return strings.ReplaceAll(html.EscapeString(src), "\n", "<br>")
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(src), &buf); err != nil {
+		return html.EscapeString(src)
+	}
+	return strings.TrimSpace(policy.Sanitize(buf.String()))
+}
+
+// RenderInline is Render for short fragments such as answer options: a lone
+// paragraph is unwrapped so the result fits inside a label.
+func RenderInline(format, src string) string {
+	out := Render(format, src)
+	if inner, ok := strings.CutPrefix(out, "<p>"); ok {
+		if inner, ok = strings.CutSuffix(inner, "</p>"); ok && !strings.Contains(inner, "<p>") {
+			return inner
+		}
+	}
+	return out
+}
@@ -1,40 +1,85 @@
 package db
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"quiz_backend/models"
+	"quiz_backend/pkg/similarity"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 type Db struct {
 	*gorm.DB
 }
 
+// ErrVersionConflict is returned when a row changed since the caller read it.
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// Tables lists the models managed by migrations.
+var Tables = []any{&models.Question{}, &models.UserSession{}, &models.IdempotencyRecord{}, &models.Media{}, &models.QuestionTranslation{}}
+
 func ConnectDb() *Db {
-	db, err := gorm.Open(sqlite.Open("quiz.db"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open("quiz.db?_busy_timeout=5000&_txlock=immediate"), &gorm.Config{
+		TranslateError: true,
+		Logger: gormlogger.NewSlogLogger(slog.Default(), gormlogger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true,
+			LogLevel:                  gormlogger.Warn,
+		}),
+	})
 	if err != nil {
-		log.Fatal("Failed to connect to database. \n", err)
+		slog.Error("failed to connect to database", "err", err)
 		os.Exit(2)
 	}
 
-	log.Println("connected db")
+	slog.Info("connected db")
 
-	return &Db{
+	conn := &Db{
 		db,
 	}
+	if err := conn.RegisterMetrics(); err != nil {
+		slog.Error("failed to register db metrics", "err", err)
+	}
+	return conn
 }
 
 func (db *Db) Migrate() {
-	db.AutoMigrate(&models.Question{}, &models.UserSession{})
-	log.Println("Database Migration Completed...")
+	db.AutoMigrate(Tables...)
+	slog.Info("database migration completed")
+}
+
+// Ping checks that the underlying connection is alive.
+func (db *Db) Ping(ctx context.Context) error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
 
-func (db *Db) SeedQuiz() error {
+// CheckMigrations reports the first model whose table is missing.
+func (db *Db) CheckMigrations() error {
+	for _, model := range Tables {
+		if !db.Migrator().HasTable(model) {
+			return fmt.Errorf("table for %T is missing", model)
+		}
+	}
+	return nil
+}
+
+// SeedQuiz imports pkg/db/questions.json and warns about imported questions
+// that score at least duplicateThreshold against an earlier one.
+func (db *Db) SeedQuiz(duplicateThreshold float64) error {
 	path := filepath.Join(".", "pkg", "db", "questions.json")
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -46,12 +91,20 @@ func (db *Db) SeedQuiz() error {
 		return err
 	}
 
+	idx := similarity.NewIndex(nil)
 	for _, question := range questions {
 		if err := db.Create(&question).Error; err != nil {
 			return err
 		}
+
+		doc := similarity.Document{ID: question.ID, Text: question.Text, Options: question.Options}
+		if matches := idx.Similar(doc, duplicateThreshold, 1); len(matches) > 0 {
+			slog.Warn("seeded question looks like a duplicate",
+				"question_id", question.ID, "similar_to", matches[0].ID, "score", matches[0].Score)
+		}
+		idx.Add(doc)
 	}
 
-	log.Println("Database seeded successfully!")
+	slog.Info("database seeded", "questions", len(questions))
 	return nil
 }
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"quiz_backend/models"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyStore keeps Idempotency-Key records in the database.
+type IdempotencyStore struct {
+	db *Db
+}
+
+func NewIdempotencyStore(db *Db) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Reserve claims key for a new request. When the key is already taken it
+// returns the existing record instead and reserved is false.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (existing *models.IdempotencyRecord, reserved bool, err error) {
+	conn := s.db.WithContext(ctx)
+	now := time.Now()
+
+	if err := conn.Where("expires_at < ?", now).Delete(&models.IdempotencyRecord{}).Error; err != nil {
+		return nil, false, err
+	}
+
+	rec := &models.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	res := conn.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	var found models.IdempotencyRecord
+	if err := conn.Where("key = ?", key).First(&found).Error; err != nil {
+		return nil, false, err
+	}
+	return &found, false, nil
+}
+
+// Complete stores the response of the request that reserved key.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, status int, header http.Header, body []byte) error {
+	stored := make(map[string]string, len(header))
+	for k := range header {
+		stored[k] = header.Get(k)
+	}
+	return s.db.WithContext(ctx).Model(&models.IdempotencyRecord{}).
+		Where("key = ?", key).
+		Select("completed", "status", "header", "body").
+		Updates(&models.IdempotencyRecord{
+			Completed: true,
+			Status:    status,
+			Header:    stored,
+			Body:      body,
+		}).Error
+}
+
+// Release drops a reservation so the request can be retried.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&models.IdempotencyRecord{}).Error
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"quiz_backend/pkg/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// RegisterMetrics times every GORM statement and reports it to the
+// quiz_db_query_duration_seconds histogram.
+func (db *Db) RegisterMetrics() error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, h := range hooks {
+		if err := h.before("metrics:before_"+h.operation, startTimer); err != nil {
+			return err
+		}
+		if err := h.after("metrics:after_"+h.operation, observeDuration(h.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startTimer(tx *gorm.DB) {
+	tx.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observeDuration(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := tx.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		metrics.DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
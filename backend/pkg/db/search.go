@@ -0,0 +1,62 @@
+package db
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// questionsFTSSchema keeps questions_fts in sync with live (not soft-deleted)
+// questions. Options are indexed as one space-separated string.
+var questionsFTSSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS questions_fts USING fts5(text, options, tokenize = 'unicode61 remove_diacritics 2')`,
+	`CREATE TRIGGER IF NOT EXISTS questions_fts_ai AFTER INSERT ON questions BEGIN
+		INSERT INTO questions_fts(rowid, text, options)
+		SELECT new.id, new.text, (SELECT group_concat(value, ' ') FROM json_each(new.options))
+		WHERE new.deleted_at IS NULL;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS questions_fts_au AFTER UPDATE ON questions BEGIN
+		DELETE FROM questions_fts WHERE rowid = old.id;
+		INSERT INTO questions_fts(rowid, text, options)
+		SELECT new.id, new.text, (SELECT group_concat(value, ' ') FROM json_each(new.options))
+		WHERE new.deleted_at IS NULL;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS questions_fts_ad AFTER DELETE ON questions BEGIN
+		DELETE FROM questions_fts WHERE rowid = old.id;
+	END`,
+}
+
+// SetupFullTextSearch creates the FTS5 index over questions and rebuilds it
+// from the current rows. It returns false when the database is not SQLite or
+// the driver was built without FTS5 (build tag sqlite_fts5); callers should
+// then fall back to plain LIKE search.
+func (db *Db) SetupFullTextSearch() (bool, error) {
+	if db.Dialector.Name() != "sqlite" {
+		return false, nil
+	}
+
+	if err := db.Exec(questionsFTSSchema[0]).Error; err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			slog.Warn("sqlite built without FTS5, using LIKE search (build with -tags sqlite_fts5)")
+			return false, nil
+		}
+		return false, err
+	}
+	for _, stmt := range questionsFTSSchema[1:] {
+		if err := db.Exec(stmt).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if err := db.Exec(`DELETE FROM questions_fts`).Error; err != nil {
+		return false, err
+	}
+	err := db.Exec(`INSERT INTO questions_fts(rowid, text, options)
+		SELECT id, text, (SELECT group_concat(value, ' ') FROM json_each(questions.options))
+		FROM questions WHERE deleted_at IS NULL`).Error
+	if err != nil {
+		return false, err
+	}
+
+	slog.Info("full-text search enabled")
+	return true, nil
+}
@@ -0,0 +1,54 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7386).
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const ContentType = "application/merge-patch+json"
+
+// Apply merges patch into doc and returns the resulting document. Members set
+// to null in the patch are removed, objects are merged recursively and every
+// other value replaces the target member as a whole.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if err := decode(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+
+	var p any
+	if err := decode(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = map[string]any{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = merge(tm[k], v)
+	}
+	return tm
+}
+
+func decode(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
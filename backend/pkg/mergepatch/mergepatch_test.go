@@ -0,0 +1,58 @@
+package mergepatch
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"member replaced", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"member added", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null deletes a member", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"null on a missing member", `{"a":"b"}`, `{"c":null}`, `{"a":"b"}`},
+		{"nested objects merge", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"x","d":null,"f":"g"}}`, `{"a":{"b":"x","f":"g"}}`},
+		{"object replaces a scalar", `{"a":"c"}`, `{"a":{"b":"c"}}`, `{"a":{"b":"c"}}`},
+		{"arrays are replaced whole", `{"a":["b","c","d"]}`, `{"a":["x"]}`, `{"a":["x"]}`},
+		{"array replaces an object", `{"a":{"b":"c"}}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"array patch replaces the target", `{"a":"b"}`, `["c"]`, `["c"]`},
+		{"scalar patch replaces the target", `{"a":"b"}`, `"c"`, `"c"`},
+		{"null patch replaces the target", `{"a":"b"}`, `null`, `null`},
+		{"empty document", ``, `{"a":{"b":null,"c":"d"}}`, `{"a":{"c":"d"}}`},
+		{"numbers kept exactly", `{"a":12345678901234567890}`, `{"b":1.50}`, `{"a":12345678901234567890,"b":1.50}`},
+		{"empty patch", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Apply(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+	}{
+		{"invalid patch", `{"a":"b"}`, `{"a":`},
+		{"invalid document", `{"a":`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply([]byte(tt.doc), []byte(tt.patch)); err == nil {
+				t.Errorf("Apply(%s, %s) succeeded, want an error", tt.doc, tt.patch)
+			}
+		})
+	}
+}
@@ -25,8 +25,9 @@ func CorsMiddleware(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID, If-Match, If-None-Match, Idempotency-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag, Idempotent-Replayed")
 
 		if r.Method == http.MethodOptions {
 			return
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"quiz_backend/models"
+	"quiz_backend/pkg/logger"
+	"quiz_backend/pkg/response"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// Bodies of keyed requests up to this size are held in memory; larger
+	// ones, such as media uploads, are spooled to a temporary file.
+	maxBufferedBodyBytes = 1 << 20
+)
+
+// replayedHeaders are the response headers restored on replay. Headers added
+// by outer middlewares (CORS, X-Request-ID) are produced fresh on every call.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location", "Retry-After"}
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*models.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, status int, header http.Header, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency makes POST, PUT, PATCH and DELETE requests that carry an
+// Idempotency-Key header safe to retry: the first response is stored for ttl
+// and replayed for later requests with the same key. Reusing a key with a
+// different method, path or body is rejected with 422, and a retry that
+// arrives while the first request is still running gets 409. Keyed bodies
+// larger than maxBody, which should cover the largest body any route
+// accepts, are rejected with 413. Keys are scoped to the caller, identified
+// by the sessionCookie cookie or else its address, so clients can neither
+// collide with nor replay each other's keys.
+func Idempotency(store IdempotencyStore, ttl time.Duration, maxBody int64, sessionCookie string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				response.BadRequest(w, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			key = scopedKey(r, sessionCookie, key)
+
+			log := logger.FromContext(r.Context())
+			fingerprint, cleanup, err := spoolBody(r, http.MaxBytesReader(w, r.Body, maxBody))
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				response.PayloadTooLarge(w, "Request body too large")
+				return
+			case err != nil:
+				log.Error("read idempotent request body", "err", err)
+				response.BadRequest(w, "Failed to read request body")
+				return
+			}
+			defer cleanup()
+
+			existing, reserved, err := store.Reserve(r.Context(), key, fingerprint, ttl)
+			if err != nil {
+				log.Error("reserve idempotency key", "err", err)
+				response.InternalError(w, "Internal server error")
+				return
+			}
+			if !reserved {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					response.WriteProblem(w, response.NewProblem(http.StatusUnprocessableEntity,
+						response.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request"))
+				case !existing.Completed:
+					response.Conflict(w, "A request with this Idempotency-Key is still being processed")
+				default:
+					replay(w, existing)
+				}
+				return
+			}
+
+			rec := &bufferingWriter{ResponseWriter: w}
+			defer func() {
+				// Nothing reusable was produced: free the key for a retry.
+				if p := recover(); p != nil {
+					_ = store.Release(context.WithoutCancel(r.Context()), key)
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+
+			ctx := context.WithoutCancel(r.Context())
+			if rec.Status() >= http.StatusInternalServerError {
+				if err := store.Release(ctx, key); err != nil {
+					log.Error("release idempotency key", "err", err)
+				}
+				return
+			}
+
+			header := http.Header{}
+			for _, h := range replayedHeaders {
+				if v := w.Header().Get(h); v != "" {
+					header.Set(h, v)
+				}
+			}
+			if err := store.Complete(ctx, key, rec.Status(), header, rec.buf.Bytes()); err != nil {
+				log.Error("store idempotent response", "err", err)
+			}
+		})
+	}
+}
+
+// scopedKey prefixes key with a hash of the caller's identity: its session
+// when it has one, else the address it connects from.
+func scopedKey(r *http.Request, sessionCookie, key string) string {
+	caller := "addr:" + r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		caller = "addr:" + host
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil && c.Value != "" {
+		caller = "session:" + c.Value
+	}
+	h := sha256.Sum256([]byte(caller))
+	return hex.EncodeToString(h[:16]) + ":" + key
+}
+
+func replay(w http.ResponseWriter, rec *models.IdempotencyRecord) {
+	for k, v := range rec.Header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	_, _ = w.Write(rec.Body)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// spoolBody reads body, fingerprinting the request on the way, and puts it
+// back on r for the handler: in memory when small, else in a temporary file
+// that cleanup removes.
+func spoolBody(r *http.Request, body io.Reader) (fingerprint string, cleanup func(), err error) {
+	h := sha256.New()
+	h.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n"))
+	src := io.TeeReader(body, h)
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, src, maxBufferedBodyBytes+1)
+	if err != nil && err != io.EOF {
+		return "", nil, err
+	}
+	if n <= maxBufferedBodyBytes {
+		r.Body = io.NopCloser(&buf)
+		return sum(h), func() {}, nil
+	}
+
+	f, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	if _, err := io.Copy(f, io.MultiReader(&buf, src)); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	r.Body = io.NopCloser(f)
+	return sum(h), cleanup, nil
+}
+
+func sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bufferingWriter passes the response through while keeping a copy of it.
+type bufferingWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (bw *bufferingWriter) WriteHeader(status int) {
+	if bw.status == 0 {
+		bw.status = status
+	}
+	bw.ResponseWriter.WriteHeader(status)
+}
+
+func (bw *bufferingWriter) Write(b []byte) (int, error) {
+	if bw.status == 0 {
+		bw.status = http.StatusOK
+	}
+	bw.buf.Write(b)
+	return bw.ResponseWriter.Write(b)
+}
+
+func (bw *bufferingWriter) Status() int {
+	if bw.status == 0 {
+		return http.StatusOK
+	}
+	return bw.status
+}
+
+func (bw *bufferingWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"quiz_backend/pkg/logger"
+	"time"
+)
+
+// Logging writes one JSON line per request and stores a logger tagged with the
+// request ID in the request context. The session cookie value is never logged,
+// only a short hash of it so requests of one player can be correlated.
+func Logging(log *slog.Logger, sessionCookie string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqLog := log.With("request_id", RequestIDFromContext(r.Context()))
+
+			rec := newStatusRecorder(w)
+			r = r.WithContext(logger.WithContext(r.Context(), reqLog))
+			next.ServeHTTP(rec, r)
+
+			route := routePattern(r)
+
+			attrs := []any{
+				"method", r.Method,
+				"route", route,
+				"path", r.URL.Path,
+				"status", rec.Status(),
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"bytes", rec.bytes,
+			}
+			if c, err := r.Cookie(sessionCookie); err == nil && c.Value != "" {
+				attrs = append(attrs, "session", hashSession(c.Value))
+			}
+
+			level := slog.LevelInfo
+			if rec.Status() >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLog.Log(r.Context(), level, "request", attrs...)
+		})
+	}
+}
+
+func hashSession(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
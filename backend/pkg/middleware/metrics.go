@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"quiz_backend/pkg/metrics"
+	"strconv"
+	"time"
+)
+
+// Metrics records request counts and latency per route pattern. Requests that
+// did not match any registered pattern share the "unmatched" label so the
+// series count stays bounded.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		route := routePattern(r)
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(rec.Status())).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
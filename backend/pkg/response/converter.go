@@ -1,70 +1,234 @@
 package response
 
 import (
+	"cmp"
+	"math"
 	"quiz_backend/models"
+	"quiz_backend/pkg/content"
+	"time"
 )
 
 func ToAdminPanelQuestionDTO(q *models.Question) models.AdminPanelQuestionDTO {
 	return models.AdminPanelQuestionDTO{
 		ID:            q.ID,
 		Text:          q.Text,
+		Format:        q.Format,
 		Options:       q.Options,
 		CorrectAnswer: q.CorrectAnswer,
+		Media:         q.Media,
+		OptionMedia:   q.OptionMedia,
+		Hint:          q.Hint,
+		Weight:        q.Weight,
+		Explanation:   q.Explanation,
+		References:    q.References,
+		Version:       q.Version,
 	}
 }
 
-func ToAdminPanelQuestionsDTO(questions []models.Question, total, pages int64, page int) models.AdminPanelQuestionsDTO {
+func ToQuestionDataDTO(q *models.Question) models.QuestionDataDTO {
+	return models.QuestionDataDTO{
+		Text:          q.Text,
+		Format:        q.Format,
+		Options:       q.Options,
+		CorrectAnswer: q.CorrectAnswer,
+		Media:         q.Media,
+		OptionMedia:   q.OptionMedia,
+		Hint:          q.Hint,
+		Weight:        q.Weight,
+		Explanation:   q.Explanation,
+		References:    q.References,
+	}
+}
+
+// ToQuestion builds the stored form of a validated question. The format
+// defaults to plain, the weight to 1 and option media without any key is dropped.
+func ToQuestion(d models.QuestionDataDTO) *models.Question {
+	q := &models.Question{
+		Text:          d.Text,
+		Format:        cmp.Or(d.Format, content.FormatPlain),
+		Options:       d.Options,
+		CorrectAnswer: d.CorrectAnswer,
+		Media:         d.Media,
+		Hint:          d.Hint,
+		Weight:        cmp.Or(d.Weight, 1),
+		Explanation:   d.Explanation,
+		References:    d.References,
+	}
+	for _, key := range d.OptionMedia {
+		if key != "" {
+			q.OptionMedia = d.OptionMedia
+			break
+		}
+	}
+	return q
+}
+
+func ToAdminPanelQuestionsDTO(questions []models.Question, highlights map[uint]models.SearchHighlight, total, pages int64, page int, next, prev string) models.AdminPanelQuestionsDTO {
 	dtos := make([]models.AdminPanelQuestionDTO, len(questions))
 	for i := range questions {
 		dtos[i] = ToAdminPanelQuestionDTO(&questions[i])
+		if hl, ok := highlights[questions[i].ID]; ok {
+			dtos[i].Highlight = &hl
+		}
 	}
 	return models.AdminPanelQuestionsDTO{
-		Questions: dtos,
-		Total:     total,
-		Pages:     pages,
-		Page:      page,
+		Questions:  dtos,
+		Total:      total,
+		Pages:      pages,
+		Page:       page,
+		NextCursor: next,
+		PrevCursor: prev,
 	}
 }
 
 func ToQuestionDTO(q *models.Question) models.QuestionDTO {
-	return models.QuestionDTO{
-		ID:        q.ID,
-		Text:      q.Text,
-		Options:   q.Options,
-		TimeLimit: models.QuestionTimeLimit,
+	dto := models.QuestionDTO{
+		ID:          q.ID,
+		Text:        q.Text,
+		Options:     q.Options,
+		Locale:      q.Locale,
+		Format:      q.Format,
+		TextHTML:    content.Render(q.Format, q.Text),
+		OptionsHTML: make([]string, len(q.Options)),
+		MediaURL:    MediaURL(q.Media),
+		TimeLimit:   models.QuestionTimeLimit,
+	}
+	for i, o := range q.Options {
+		dto.OptionsHTML[i] = content.RenderInline(q.Format, o)
+	}
+	if len(q.OptionMedia) > 0 {
+		dto.OptionMediaURLs = make([]string, len(q.OptionMedia))
+		for i, key := range q.OptionMedia {
+			dto.OptionMediaURLs[i] = MediaURL(key)
+		}
+	}
+	return dto
+}
+
+// ExplanationHTML renders the question's markdown explanation, "" for none.
+func ExplanationHTML(q *models.Question) string {
+	if q.Explanation == "" {
+		return ""
+	}
+	return content.Render(content.FormatMarkdown, q.Explanation)
+}
+
+// MediaURL is the path a media key is served under, "" for no media.
+func MediaURL(key string) string {
+	if key == "" {
+		return ""
 	}
+	return "/api/v1/media/" + key
 }
 
-func ToCheckResponse(session *models.UserSession) models.SessionStats {
-	return models.SessionStats{
-		CurrentIndex:   session.CurrentIndex,
-		HasActiveGame:  session.HasActiveGame,
-		TotalCorrect:   session.CorrectAnswers,
-		TotalIncorrect: session.IncorrectAnswers,
+func ToMediaDTO(m *models.Media) models.MediaDTO {
+	return models.MediaDTO{
+		Key:         m.Key,
+		URL:         MediaURL(m.Key),
+		ContentType: m.ContentType,
+		Size:        m.Size,
 	}
 }
 
-func ToAnswerResponse(correct bool, answerIdx int, reason string, session *models.UserSession, nextQ *models.Question) models.AnswerResponse {
+// ToCheckResponse converts the session's progress. reveal tells whether the
+// feedback policy lets the player see the totals.
+func ToCheckResponse(session *models.UserSession, reveal bool) models.SessionStats {
+	stats := models.SessionStats{
+		CurrentIndex:  session.CurrentIndex,
+		HasActiveGame: session.HasActiveGame,
+	}
+	if reveal {
+		score := ToScoreDTO(session)
+		stats.TotalCorrect = &session.CorrectAnswers
+		stats.TotalIncorrect = &session.IncorrectAnswers
+		stats.Score = &score
+	}
+	stats.Paused = session.PausedAt != nil
+	stats.ServerTime = time.Now()
+	if session.HasActiveGame && session.RoundDeadline != nil {
+		left := max(int(math.Ceil(session.RoundDeadline.Sub(session.Clock()).Seconds())), 0)
+		stats.RoundTimeLeft = &left
+		if !stats.Paused {
+			stats.RoundDeadline = session.RoundDeadline
+		}
+	}
+	if session.HasActiveGame && session.NavigationMode == models.NavigationFree {
+		stats.Navigation = ToNavigationDTO(session)
+	}
+	return stats
+}
+
+// ToNavigationDTO lists the drafts, flags and time left of every question of
+// a free-navigation round.
+func ToNavigationDTO(session *models.UserSession) *models.NavigationDTO {
+	dto := &models.NavigationDTO{
+		Mode:      session.NavigationMode,
+		Timer:     session.NavigationTimer,
+		Questions: make([]models.NavigationItemDTO, len(session.Questions)),
+	}
+	for i := range session.Questions {
+		spent := session.Spent[i]
+		if i == session.CurrentIndex && session.QuestionStartTime != nil {
+			spent += int(session.Clock().Sub(*session.QuestionStartTime).Milliseconds())
+		}
+		item := models.NavigationItemDTO{
+			Idx:      i,
+			Answer:   session.Drafts[i],
+			Answered: session.Drafts[i] != -1,
+			Flagged:  session.Flags[i],
+		}
+		if session.NavigationTimer == models.TimerQuestion {
+			item.Locked = spent > models.ServerTimeLimit*1000
+			item.TimeLeft = max(models.QuestionTimeLimit-spent/1000, 0)
+		}
+		dto.Questions[i] = item
+	}
+	return dto
+}
+
+// ToScoreDTO sums the points of the session's round by component.
+func ToScoreDTO(session *models.UserSession) models.ScoreDTO {
+	dto := models.ScoreDTO{Policy: session.ScoringPolicy, Total: session.Score, Streak: session.Streak}
+	for _, rec := range session.Answers {
+		dto.Base += rec.Score.Base
+		dto.SpeedBonus += rec.Score.SpeedBonus
+		dto.StreakBonus += rec.Score.StreakBonus
+		dto.Penalty += rec.Score.Penalty
+	}
+	return dto
+}
+
+// ToAnswerResponse converts the recorded outcome of an answer to the answered
+// question, answerIdx being its correct option in the player's order. Without
+// reveal the result, the explanation and the score are withheld and only a
+// timeout is reported as such.
+func ToAnswerResponse(answered *models.Question, rec models.AnswerRecord, answerIdx int, reveal bool, session *models.UserSession, nextQ *models.Question) models.AnswerResponse {
 	var dto *models.QuestionDTO
 	if nextQ != nil {
 		q := ToQuestionDTO(nextQ)
 		dto = &q
 	}
-	return models.AnswerResponse{
-		Correct: correct,
-		Reason:  reason,
-		Answer:  answerIdx,
-		SessionStats: models.SessionStats{
-			CurrentIndex:   session.CurrentIndex,
-			HasActiveGame:  session.HasActiveGame,
-			TotalCorrect:   session.CorrectAnswers,
-			TotalIncorrect: session.IncorrectAnswers,
-		},
+	resp := models.AnswerResponse{
+		Reason:       rec.Reason,
+		SessionStats: ToCheckResponse(session, reveal),
 		NextQuestion: dto,
 	}
+	if reveal {
+		resp.Correct = &rec.Correct
+		resp.Answer = &answerIdx
+		resp.Points = &rec.Score
+		resp.ExplanationHTML = ExplanationHTML(answered)
+		resp.References = answered.References
+	} else {
+		resp.FeedbackWithheld = true
+		if rec.Reason != "timeout" {
+			resp.Reason = "recorded"
+		}
+	}
+	return resp
 }
 
-func ToStartResponse(timeLimit int, session *models.UserSession, nextQ *models.Question) models.StartResponse {
+func ToStartResponse(timeLimit int, reveal bool, session *models.UserSession, nextQ *models.Question) models.StartResponse {
 	var dto *models.QuestionDTO
 	if nextQ != nil {
 		q := ToQuestionDTO(nextQ)
@@ -72,12 +236,18 @@ func ToStartResponse(timeLimit int, session *models.UserSession, nextQ *models.Q
 		dto.TimeLimit = timeLimit
 	}
 	return models.StartResponse{
-		SessionStats: models.SessionStats{
-			CurrentIndex:   session.CurrentIndex,
-			HasActiveGame:  session.HasActiveGame,
-			TotalCorrect:   session.CorrectAnswers,
-			TotalIncorrect: session.IncorrectAnswers,
-		},
+		SessionStats: ToCheckResponse(session, reveal),
 		NextQuestion: dto,
 	}
 }
+
+func ToTranslationDTO(t *models.QuestionTranslation, stale bool) models.TranslationDTO {
+	return models.TranslationDTO{
+		Locale:    t.Locale,
+		Text:      t.Text,
+		Options:   t.Options,
+		Hint:      t.Hint,
+		UpdatedAt: t.UpdatedAt,
+		Stale:     stale,
+	}
+}
@@ -0,0 +1,102 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"quiz_backend/models"
+	"quiz_backend/pkg/db"
+	"quiz_backend/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Machine-readable error codes carried in Problem.Code.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeUnauthorized         = "unauthorized"
+	CodeFeedbackLocked       = "feedback_locked"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodePreconditionFailed   = "precondition_failed"
+	CodePreconditionRequired = "precondition_required"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeDuplicateQuestion    = "duplicate_question"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details object. Code, Errors and Duplicates
+// are extension members; RequestID echoes the X-Request-ID of the failed
+// request.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Duplicates lists the existing questions a rejected one is too close to.
+	Duplicates []models.DuplicateMatchDTO `json:"duplicates,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "urn:quiz:problem:" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	if p.RequestID == "" {
+		p.RequestID = w.Header().Get("X-Request-ID")
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// FromError maps a repository error to a problem response and logs it with
+// the request ID. resource names the entity in client-facing messages, e.g.
+// "Question" becomes "Question not found".
+func FromError(w http.ResponseWriter, r *http.Request, err error, resource string) {
+	var p Problem
+	switch {
+	case errors.Is(err, db.ErrVersionConflict):
+		p = NewProblem(http.StatusPreconditionFailed, CodePreconditionFailed, resource+" was modified by someone else, reload and retry")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		p = NewProblem(http.StatusNotFound, CodeNotFound, resource+" not found")
+	case errors.Is(err, gorm.ErrDuplicatedKey), errors.Is(err, gorm.ErrForeignKeyViolated):
+		p = NewProblem(http.StatusConflict, CodeConflict, resource+" conflicts with existing data")
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		p = NewProblem(http.StatusUnprocessableEntity, CodeValidationFailed, resource+" violates a data constraint")
+	default:
+		p = NewProblem(http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+	p.Instance = r.URL.Path
+
+	log := logger.FromContext(r.Context())
+	if p.Status >= http.StatusInternalServerError {
+		log.Error("request failed", "path", r.URL.Path, "status", p.Status, "err", err)
+	} else {
+		log.Debug("request rejected", "path", r.URL.Path, "status", p.Status, "err", err)
+	}
+	WriteProblem(w, p)
+}
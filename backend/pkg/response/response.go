@@ -3,6 +3,9 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+	"quiz_backend/models"
+	"strconv"
+	"time"
 )
 
 func JsonResp[T any](w http.ResponseWriter, data T, status int) {
@@ -19,14 +22,65 @@ func Created[T any](w http.ResponseWriter, data T) {
 	JsonResp(w, data, http.StatusCreated)
 }
 
-func BadRequest(w http.ResponseWriter, msg string) {
-	JsonResp(w, map[string]string{"error": msg}, http.StatusBadRequest)
+func BadRequest(w http.ResponseWriter, msg string, errs ...FieldError) {
+	p := NewProblem(http.StatusBadRequest, CodeBadRequest, msg)
+	p.Errors = errs
+	WriteProblem(w, p)
 }
 
 func Unauthorized(w http.ResponseWriter, msg string) {
-	JsonResp(w, map[string]string{"error": msg}, http.StatusUnauthorized)
+	WriteProblem(w, NewProblem(http.StatusUnauthorized, CodeUnauthorized, msg))
+}
+
+func FeedbackLocked(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusForbidden, CodeFeedbackLocked, msg))
+}
+
+func NotFound(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusNotFound, CodeNotFound, msg))
+}
+
+func Conflict(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusConflict, CodeConflict, msg))
+}
+
+func DuplicateQuestion(w http.ResponseWriter, msg string, matches []models.DuplicateMatchDTO) {
+	p := NewProblem(http.StatusConflict, CodeDuplicateQuestion, msg)
+	p.Duplicates = matches
+	WriteProblem(w, p)
+}
+
+func PreconditionFailed(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusPreconditionFailed, CodePreconditionFailed, msg))
+}
+
+func PreconditionRequired(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusPreconditionRequired, CodePreconditionRequired, msg))
+}
+
+func PayloadTooLarge(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, msg))
+}
+
+func UnsupportedMediaType(w http.ResponseWriter, msg string) {
+	WriteProblem(w, NewProblem(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, msg))
+}
+
+func UnprocessableEntity(w http.ResponseWriter, msg string, errs ...FieldError) {
+	p := NewProblem(http.StatusUnprocessableEntity, CodeValidationFailed, msg)
+	p.Errors = errs
+	WriteProblem(w, p)
+}
+
+// TooManyRequests also sets Retry-After when retryAfter is positive.
+func TooManyRequests(w http.ResponseWriter, msg string, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		secs := int((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+	}
+	WriteProblem(w, NewProblem(http.StatusTooManyRequests, CodeRateLimited, msg))
 }
 
 func InternalError(w http.ResponseWriter, msg string) {
-	JsonResp(w, map[string]string{"error": msg}, http.StatusInternalServerError)
+	WriteProblem(w, NewProblem(http.StatusInternalServerError, CodeInternal, msg))
 }
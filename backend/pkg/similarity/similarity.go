@@ -0,0 +1,174 @@
+// Package similarity scores how alike two quiz questions are. Text is compared
+// by TF-IDF cosine over word unigrams and bigrams (shingles), options by the
+// overlap of their normalised sets. Everything is computed in memory.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Weights of the text and option scores in the combined score.
+const (
+	textWeight   = 0.7
+	optionWeight = 0.3
+)
+
+// Document is a question as seen by the index.
+type Document struct {
+	ID      uint
+	Text    string
+	Options []string
+}
+
+// Match is an indexed document scored against a probe. Scores are in [0, 1].
+type Match struct {
+	ID          uint
+	Score       float64
+	TextScore   float64
+	OptionScore float64
+}
+
+// Index holds a corpus of questions with their term weights.
+type Index struct {
+	docs    []indexed
+	docFreq map[string]int
+}
+
+type indexed struct {
+	Document
+	terms   map[string]float64
+	options map[string]struct{}
+}
+
+// NewIndex builds an index over docs. The IDF statistics come from docs, so
+// terms every question shares ("what", "which") weigh next to nothing.
+func NewIndex(docs []Document) *Index {
+	idx := &Index{docFreq: make(map[string]int)}
+	for _, d := range docs {
+		idx.Add(d)
+	}
+	return idx
+}
+
+// Add puts d into the index.
+func (idx *Index) Add(d Document) {
+	terms := termCounts(d.Text)
+	for t := range terms {
+		idx.docFreq[t]++
+	}
+	idx.docs = append(idx.docs, indexed{Document: d, terms: terms, options: optionSet(d.Options)})
+}
+
+// Similar returns the indexed documents (other than d.ID) scoring at least
+// threshold against d, best first, at most limit of them (0 for all).
+func (idx *Index) Similar(d Document, threshold float64, limit int) []Match {
+	probe := indexed{Document: d, terms: termCounts(d.Text), options: optionSet(d.Options)}
+	pv := idx.vector(probe.terms)
+
+	var matches []Match
+	for i := range idx.docs {
+		doc := &idx.docs[i]
+		if d.ID != 0 && doc.ID == d.ID {
+			continue
+		}
+		if m := idx.score(&probe, pv, doc); m.Score >= threshold {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func (idx *Index) score(probe *indexed, pv map[string]float64, doc *indexed) Match {
+	m := Match{
+		ID:          doc.ID,
+		TextScore:   cosine(pv, idx.vector(doc.terms)),
+		OptionScore: jaccard(probe.options, doc.options),
+	}
+	if len(probe.options) == 0 || len(doc.options) == 0 {
+		m.Score = m.TextScore
+	} else {
+		m.Score = textWeight*m.TextScore + optionWeight*m.OptionScore
+	}
+	return m
+}
+
+// vector weights term counts by smoothed IDF.
+func (idx *Index) vector(counts map[string]float64) map[string]float64 {
+	n := float64(len(idx.docs))
+	v := make(map[string]float64, len(counts))
+	for t, c := range counts {
+		v[t] = c * (math.Log((1+n)/(1+float64(idx.docFreq[t]))) + 1)
+	}
+	return v
+}
+
+// Normalize lowercases s and splits it into words, dropping punctuation.
+func Normalize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// termCounts counts the words of s and its two-word shingles.
+func termCounts(s string) map[string]float64 {
+	words := Normalize(s)
+	counts := make(map[string]float64, 2*len(words))
+	for i, w := range words {
+		counts[w]++
+		if i > 0 {
+			counts[words[i-1]+" "+w]++
+		}
+	}
+	return counts
+}
+
+func optionSet(options []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(options))
+	for _, o := range options {
+		if n := strings.Join(Normalize(o), " "); n != "" {
+			set[n] = struct{}{}
+		}
+	}
+	return set
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for t, x := range a {
+		na += x * x
+		dot += x * b[t]
+	}
+	for _, y := range b {
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
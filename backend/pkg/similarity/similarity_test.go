@@ -0,0 +1,101 @@
+package similarity
+
+import (
+	"strings"
+	"testing"
+)
+
+// testThreshold is the default DUPLICATE_THRESHOLD.
+const testThreshold = 0.6
+
+var corpus = []Document{
+	{ID: 1, Text: "What is the capital of France?", Options: []string{"Paris", "Lyon", "Nice", "Lille"}},
+	{ID: 2, Text: "Which planet is known as the Red Planet?", Options: []string{"Mars", "Venus", "Jupiter", "Saturn"}},
+	{ID: 3, Text: "Who wrote Romeo and Juliet?", Options: []string{"Shakespeare", "Dickens", "Austen", "Tolstoy"}},
+	{ID: 4, Text: "What does CSS stand for?", Options: []string{"Cascading Style Sheets", "Colorful Style Sheets", "Computer Style Sheets", "Creative Style Sheets"}},
+}
+
+func TestSimilarScores(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     Document
+		min, max  float64
+		duplicate bool
+	}{
+		{"identical", Document{Text: "What is the capital of France?", Options: []string{"Paris", "Lyon", "Nice", "Lille"}},
+			1, 1, true},
+		{"casing and punctuation", Document{Text: "WHAT is the Capital of FRANCE!!", Options: []string{"paris", "LYON", "Nice.", "Lille"}},
+			1, 1, true},
+		{"reordered words and options", Document{Text: "The capital of France is what?", Options: []string{"Lille", "Nice", "Lyon", "Paris"}},
+			0.8, 0.82, true},
+		{"same text without options", Document{Text: "What is the capital of France?"},
+			1, 1, true},
+		{"just under the threshold", Document{Text: "What is the capital city of France?", Options: []string{"Paris", "Marseille", "Bordeaux", "Toulouse"}},
+			0.55, 0.58, false},
+		{"unrelated", Document{Text: "How many legs does a spider have?", Options: []string{"Six", "Eight", "Ten", "Twelve"}},
+			0, 0, false},
+	}
+
+	idx := NewIndex(corpus)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Match
+			for _, m := range idx.Similar(tt.probe, 0, 0) {
+				if m.ID == 1 {
+					got = m
+				}
+			}
+			// Allow for rounding in the exact cases.
+			if got.Score < tt.min-1e-9 || got.Score > tt.max+1e-9 {
+				t.Errorf("score %.4f (text %.4f, options %.4f), want in [%.2f, %.2f]",
+					got.Score, got.TextScore, got.OptionScore, tt.min, tt.max)
+			}
+
+			found := false
+			for _, m := range idx.Similar(tt.probe, testThreshold, 0) {
+				found = found || m.ID == 1
+			}
+			if found != tt.duplicate {
+				t.Errorf("reported as duplicate at %.2f: %t, want %t", testThreshold, found, tt.duplicate)
+			}
+		})
+	}
+}
+
+func TestSimilarSkipsSelfAndSorts(t *testing.T) {
+	idx := NewIndex(append(corpus, Document{ID: 5, Text: "What is the capital of Spain?", Options: []string{"Madrid", "Lyon", "Nice", "Lille"}}))
+
+	matches := idx.Similar(corpus[0], 0.1, 0)
+	if len(matches) == 0 || matches[0].ID != 5 {
+		t.Fatalf("Similar(question 1) = %+v, want question 5 first", matches)
+	}
+	for i, m := range matches {
+		if m.ID == corpus[0].ID {
+			t.Errorf("question matched itself: %+v", m)
+		}
+		if i > 0 && m.Score > matches[i-1].Score {
+			t.Errorf("matches not sorted by score: %+v", matches)
+		}
+	}
+
+	if got := idx.Similar(corpus[0], 0.1, 1); len(got) != 1 {
+		t.Errorf("Similar with limit 1 returned %d matches", len(got))
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"What's  the CAPITAL, of France?!", "what s the capital of france"},
+		{"Über-größe 42", "über größe 42"},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(Normalize(tt.in), " "); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
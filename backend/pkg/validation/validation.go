@@ -0,0 +1,235 @@
+// Package validation checks request DTOs against rules declared in their
+// `validate` struct tags and reports every failing field at once.
+//
+// Supported rules, separated by commas:
+//
+//	required        string not blank, slice not empty, pointer not nil
+//	min=N           minimum string length (runes), slice length or int value
+//	max=N           maximum string length (runes), slice length or int value
+//	oneof=A B       string is empty or one of the space-separated values
+//	unique          slice of strings without duplicates (trimmed, case-insensitive)
+//	index=Field     int is a valid index into the sibling slice Field
+//	parallel=Field  slice is empty or has one element per element of Field
+//	url             string is empty or an absolute http(s) URL
+//	dive            the rules that follow apply to every slice element; struct
+//	                elements without further rules are validated by their tags
+//
+// Field names in errors use the json tag, e.g. "options[2]" or
+// "references[0].url".
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"quiz_backend/pkg/response"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors holds every failed rule of a validated value.
+type Errors []response.FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Struct validates v, which must be a struct or a pointer to one. It returns
+// nil when all rules pass.
+func Struct(v any) Errors {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: Struct called with %T", v))
+	}
+
+	errs := checkStruct(rv, "")
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func checkStruct(rv reflect.Value, prefix string) Errors {
+	var errs Errors
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || !sf.IsExported() {
+			continue
+		}
+		errs = append(errs, checkField(rv, prefix+fieldName(sf), rv.Field(i), strings.Split(tag, ","))...)
+	}
+	return errs
+}
+
+func checkField(parent reflect.Value, name string, fv reflect.Value, rules []string) Errors {
+	var errs Errors
+	for i, rule := range rules {
+		key, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		if key == "dive" {
+			if fv.Kind() != reflect.Slice {
+				panic("validation: dive on non-slice field " + name)
+			}
+			for j := 0; j < fv.Len(); j++ {
+				elem, elemName := fv.Index(j), fmt.Sprintf("%s[%d]", name, j)
+				if elem.Kind() == reflect.Struct && i == len(rules)-1 {
+					errs = append(errs, checkStruct(elem, elemName+".")...)
+					continue
+				}
+				errs = append(errs, checkField(parent, elemName, elem, rules[i+1:])...)
+			}
+			return errs
+		}
+
+		fe, ok := apply(parent, fv, key, arg)
+		if ok {
+			continue
+		}
+		fe.Field = name
+		errs = append(errs, fe)
+
+		// Once a value is missing the remaining rules only add noise.
+		if key == "required" {
+			return errs
+		}
+	}
+	return errs
+}
+
+func apply(parent, fv reflect.Value, key, arg string) (response.FieldError, bool) {
+	switch key {
+	case "required":
+		if isBlank(fv) {
+			return response.FieldError{Code: "required", Message: "is required"}, false
+		}
+	case "min", "max":
+		return checkBound(fv, key, mustAtoi(key, arg))
+	case "oneof":
+		if v := fv.String(); v != "" && !slices.Contains(strings.Fields(arg), v) {
+			return response.FieldError{Code: "invalid", Message: "must be one of " + strings.Join(strings.Fields(arg), ", ")}, false
+		}
+	case "unique":
+		if dup, ok := firstDuplicate(fv); ok {
+			return response.FieldError{Code: "duplicate", Message: fmt.Sprintf("must not repeat %q", dup)}, false
+		}
+	case "index":
+		sibling := parent.FieldByName(arg)
+		if !sibling.IsValid() || sibling.Kind() != reflect.Slice {
+			panic("validation: index refers to unknown slice field " + arg)
+		}
+		if n := fv.Int(); n < 0 || n >= int64(sibling.Len()) {
+			return response.FieldError{
+				Code:    "out_of_range",
+				Message: fmt.Sprintf("must be between 0 and %d", sibling.Len()-1),
+			}, false
+		}
+	case "parallel":
+		sibling := parent.FieldByName(arg)
+		if !sibling.IsValid() || sibling.Kind() != reflect.Slice {
+			panic("validation: parallel refers to unknown slice field " + arg)
+		}
+		if n := fv.Len(); n != 0 && n != sibling.Len() {
+			return response.FieldError{
+				Code:    "length_mismatch",
+				Message: fmt.Sprintf("must be empty or have %d items", sibling.Len()),
+			}, false
+		}
+	case "url":
+		if v := strings.TrimSpace(fv.String()); v != "" && !isWebURL(v) {
+			return response.FieldError{Code: "invalid_url", Message: "must be an absolute http or https URL"}, false
+		}
+	default:
+		panic("validation: unknown rule " + key)
+	}
+	return response.FieldError{}, true
+}
+
+func checkBound(fv reflect.Value, key string, bound int) (response.FieldError, bool) {
+	var n int
+	var unit string
+	switch fv.Kind() {
+	case reflect.String:
+		n, unit = utf8.RuneCountInString(strings.TrimSpace(fv.String())), "characters"
+	case reflect.Slice:
+		n, unit = fv.Len(), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = int(fv.Int())
+	default:
+		panic("validation: " + key + " on unsupported kind " + fv.Kind().String())
+	}
+
+	if key == "min" && n < bound {
+		if unit == "" {
+			return response.FieldError{Code: "too_small", Message: fmt.Sprintf("must be at least %d", bound)}, false
+		}
+		return response.FieldError{Code: "too_short", Message: fmt.Sprintf("must have at least %d %s", bound, unit)}, false
+	}
+	if key == "max" && n > bound {
+		if unit == "" {
+			return response.FieldError{Code: "too_large", Message: fmt.Sprintf("must be at most %d", bound)}, false
+		}
+		return response.FieldError{Code: "too_long", Message: fmt.Sprintf("must have at most %d %s", bound, unit)}, false
+	}
+	return response.FieldError{}, true
+}
+
+func isBlank(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(fv.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return fv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return fv.IsNil()
+	default:
+		return fv.IsZero()
+	}
+}
+
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func firstDuplicate(fv reflect.Value) (string, bool) {
+	if fv.Kind() != reflect.Slice || fv.Type().Elem().Kind() != reflect.String {
+		panic("validation: unique requires a slice of strings")
+	}
+	seen := make(map[string]bool, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		s := strings.TrimSpace(fv.Index(i).String())
+		if s == "" {
+			continue
+		}
+		key := strings.ToLower(s)
+		if seen[key] {
+			return s, true
+		}
+		seen[key] = true
+	}
+	return "", false
+}
+
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func mustAtoi(key, arg string) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		panic("validation: " + key + " needs an integer argument, got " + strconv.Quote(arg))
+	}
+	return n
+}
@@ -2,13 +2,15 @@ import { ApiService } from "../services/api-service";
 import { TimerService } from "../components/timer/timer-service";
 import { NEGATIVE_ANSWER, TIMEOUT_DELAY } from "../shared/constants";
 import { inject, Injectable, signal } from "angular";
-import type { IAnswer, IQuestion, IStats } from "@/shared/types.js";
+import type { IAnswer, IQuestion, IReference, IStats } from "@/shared/types.js";
 import { ToastService } from "@/components/toast/toast-service";
 
 interface IGameState {
   isGameActive: boolean;
-  correctAnswers: number;
-  incorrectAnswers: number;
+  // null while the feedback policy withholds results.
+  correctAnswers: number | null;
+  incorrectAnswers: number | null;
+  score: number | null;
   currentQuestionNumber: number;
 }
 
@@ -24,19 +26,25 @@ export class QuizService {
   gameState = signal<IGameState | null>(null);
   currentQuestion = signal<IQuestionState | null>(null);
   correctAnswer = signal<number | null>(null);
+  // Shown with the result of the last answer, when the backend sends one.
+  explanation = signal<{ html: string; references: IReference[] } | null>(null);
   haveActiveSession = signal(false);
   isActive = this.timerService.isActive;
+  // Milliseconds the server clock is ahead of this one; null until measured.
+  private clockOffset: number | null = null;
 
   init() {
+    this.syncClock();
     return this.apiService.getSession().then((resp) => resp.has_active_game);
   }
 
   start() {
     return this.apiService.startQuiz().then((resp) => {
+      this.explanation.set(null);
       this.updateGameState(resp);
       if (resp.next_question) {
         this.updateQuestionState(resp.next_question);
-        this.timerService.start(resp.next_question.time_limit);
+        this.timerService.start(this.timeLeft(resp.next_question));
       }
     });
   }
@@ -56,7 +64,13 @@ export class QuizService {
       } else {
         this.updateGameState(resp);
       }
-      this.correctAnswer.set(resp.correct_answer_idx);
+      this.correctAnswer.set(resp.correct_answer_idx ?? null);
+      if (resp.explanation_html || resp.references?.length) {
+        this.explanation.set({
+          html: resp.explanation_html ?? "",
+          references: resp.references ?? [],
+        });
+      }
       this.toastService.show(resp.reason);
 
       return new Promise((resolve) => {
@@ -64,9 +78,10 @@ export class QuizService {
           if (resp.has_active_game) {
             if (resp.next_question) {
               this.updateQuestionState(resp.next_question);
-              this.timerService.start(resp.next_question.time_limit);
+              this.timerService.start(this.timeLeft(resp.next_question));
             }
             this.correctAnswer.set(null);
+            this.explanation.set(null);
           } else {
             const prevState = this.gameState();
             if (prevState) {
@@ -82,12 +97,35 @@ export class QuizService {
     });
   }
 
+  // Takes the middle of the round trip as the moment the server read its clock.
+  private syncClock() {
+    const sent = Date.now();
+    return this.apiService
+      .getServerTime()
+      .then((resp) => {
+        this.clockOffset = resp.unix_ms - (sent + Date.now()) / 2;
+      })
+      .catch(() => {
+        this.clockOffset = null;
+      });
+  }
+
+  // Counts down to the question's deadline on the server clock, so latency
+  // and a skewed local clock cannot make the countdown disagree with the
+  // server. Falls back to time_limit while the clock offset is unknown.
+  private timeLeft(question: IQuestion) {
+    if (!question.deadline || this.clockOffset === null) return question.time_limit;
+    const serverNow = Date.now() + this.clockOffset;
+    return Math.max(Math.ceil((Date.parse(question.deadline) - serverNow) / 1000), 0);
+  }
+
   private updateGameState(state: IStats) {
-    const { has_active_game, total_correct, total_incorrect, current_index } = state;
+    const { has_active_game, total_correct, total_incorrect, current_index, score } = state;
     this.gameState.set({
       isGameActive: has_active_game,
-      correctAnswers: total_correct,
-      incorrectAnswers: total_incorrect,
+      correctAnswers: total_correct ?? null,
+      incorrectAnswers: total_incorrect ?? null,
+      score: score?.total ?? null,
       currentQuestionNumber: current_index,
     });
   }
@@ -102,10 +140,13 @@ export class QuizService {
     const prevState = this.gameState();
     if (!prevState) return;
     const { correctAnswers, incorrectAnswers, currentQuestionNumber } = prevState;
+    const withheld = correct === undefined || correctAnswers === null || incorrectAnswers === null;
     this.gameState.set({
       ...prevState,
-      correctAnswers: correct ? correctAnswers + 1 : correctAnswers,
-      incorrectAnswers: !correct ? incorrectAnswers + 1 : incorrectAnswers,
+      correctAnswers: withheld ? null : correct ? correctAnswers + 1 : correctAnswers,
+      incorrectAnswers: withheld ? null : !correct ? incorrectAnswers + 1 : incorrectAnswers,
+      // Unlike the counters the score is not reset when the round ends.
+      score: state.score?.total ?? null,
       currentQuestionNumber: currentQuestionNumber + 1,
     });
   }
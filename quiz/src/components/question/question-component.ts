@@ -8,7 +8,8 @@ import { Component, computed, inject } from "angular";
 export class QuestionComponent {
   private questionService = inject(QuestionService);
   options = computed(() => this.questionService.question()?.options, this);
-  question = computed(() => this.questionService.question()?.text, this);
+  // Rendered and sanitised by the backend.
+  questionHtml = computed(() => this.questionService.question()?.text_html ?? "", this);
   selected = computed(() => this.questionService.state().selected, this);
   isDisabled = computed(
     () =>
@@ -17,6 +18,9 @@ export class QuestionComponent {
         : this.questionService.state().isSubmiting,
     this,
   );
+  hasExplanation = computed(() => this.questionService.explanation() !== null, this);
+  explanationHtml = computed(() => this.questionService.explanation()?.html ?? "", this);
+  references = computed(() => this.questionService.explanation()?.references ?? [], this);
   isTimeout = computed(() => !this.questionService.isActive(), this);
   buttonText = computed(
     () => (!this.questionService.isActive() ? "Next question" : "Confirm answer"),
@@ -42,6 +46,19 @@ export class QuestionComponent {
     }
   }
 
+  optionHtml(index: number): string {
+    return this.questionService.question()?.options_html[index] ?? "";
+  }
+
+  referenceTitle(index: number): string {
+    const ref = this.references()[index];
+    return ref?.title || ref?.url || "";
+  }
+
+  referenceUrl(index: number): string {
+    return this.references()[index]?.url ?? "";
+  }
+
   select(idx: number) {
     if (!this.questionService.isActive()) return;
     this.questionService.select(idx);
@@ -20,6 +20,7 @@ export class QuestionService {
   state = signal<IState>(defaultState);
   question = this.quizService.currentQuestion;
   correctAnswer = this.quizService.correctAnswer;
+  explanation = this.quizService.explanation;
   isActive = this.quizService.isActive;
 
   select(answerIdx: number) {
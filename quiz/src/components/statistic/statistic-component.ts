@@ -8,7 +8,17 @@ import { STATS } from "@/shared/constants";
 })
 export class StatisticComponent {
   statService = inject(StatisticService);
-  state = computed(() => this.statService.stat(), this);
+  // Totals the feedback policy withholds are shown as a dash.
+  state = computed(() => {
+    const stat = this.statService.stat();
+    if (!stat) return stat;
+    return {
+      ...stat,
+      correctAnswers: stat.correctAnswers ?? "—",
+      incorrectAnswers: stat.incorrectAnswers ?? "—",
+      score: stat.score ?? "—",
+    };
+  }, this);
   keys = Object.keys(STATS);
   statisticText = STATS;
 }
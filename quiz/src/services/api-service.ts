@@ -1,4 +1,5 @@
 import { inject, Injectable } from "angular";
+import { API_URL } from "../shared/constants";
 import { HttpService } from "./http-service";
 import { ValidatorService } from "./validator-service";
 
@@ -24,6 +25,15 @@ export class ApiService {
     });
   }
 
+  getServerTime() {
+    return this.http.get("/time", API_URL).then((resp: unknown) => {
+      if (!ValidatorService.convertServerTimeFromDTO(resp)) {
+        throw new Error("Invalid server time");
+      }
+      return resp;
+    });
+  }
+
   postAnswer(answerIdx: number, questionIdx: number, questionId: number) {
     return this.http
       .post("/answer", {
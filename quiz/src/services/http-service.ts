@@ -4,8 +4,8 @@ import type { IPostBody } from "@/shared/types";
 
 Injectable();
 export class HttpService {
-  get(url: string) {
-    return fetch(`${BASE_URL}${url}`, {
+  get(url: string, base = BASE_URL) {
+    return fetch(`${base}${url}`, {
       credentials: "include",
     })
       .then((response) => {
@@ -1,4 +1,4 @@
-import type { IAnswer, IQuestion, IResponse, IStats } from "@/shared/types";
+import type { IAnswer, IQuestion, IResponse, IServerTime, IStats } from "@/shared/types";
 import { responseStatuses } from "../shared/constants";
 
 export class ValidatorService {
@@ -17,13 +17,29 @@ export class ValidatorService {
   static convertAnswerResponseFromDTO(data: unknown): data is IAnswer {
     return (
       this.validResponse(data) &&
-      "correct" in data &&
-      typeof data.correct === "boolean" &&
+      (!("correct" in data) || typeof data.correct === "boolean") &&
       "reason" in data &&
       typeof data.reason === "string" &&
       Object.keys(responseStatuses).includes(data.reason) &&
-      "correct_answer_idx" in data &&
-      typeof data.correct_answer_idx === "number"
+      (!("correct_answer_idx" in data) || typeof data.correct_answer_idx === "number") &&
+      (!("explanation_html" in data) || typeof data.explanation_html === "string") &&
+      (!("references" in data) ||
+        (Array.isArray(data.references) &&
+          data.references.every(
+            (ref) =>
+              typeof ref === "object" && ref !== null && "url" in ref && typeof ref.url === "string",
+          )))
+    );
+  }
+
+  static convertServerTimeFromDTO(data: unknown): data is IServerTime {
+    return (
+      typeof data === "object" &&
+      data !== null &&
+      "server_time" in data &&
+      typeof data.server_time === "string" &&
+      "unix_ms" in data &&
+      typeof data.unix_ms === "number"
     );
   }
 
@@ -31,12 +47,15 @@ export class ValidatorService {
     return (
       typeof data === "object" &&
       data !== null &&
-      "total_correct" in data &&
-      typeof data.total_correct === "number" &&
-      data.total_correct >= 0 &&
-      "total_incorrect" in data &&
-      typeof data.total_incorrect === "number" &&
-      data.total_incorrect >= 0 &&
+      (!("total_correct" in data) ||
+        (typeof data.total_correct === "number" && data.total_correct >= 0)) &&
+      (!("total_incorrect" in data) ||
+        (typeof data.total_incorrect === "number" && data.total_incorrect >= 0)) &&
+      (!("score" in data) ||
+        (typeof data.score === "object" &&
+          data.score !== null &&
+          "total" in data.score &&
+          typeof data.score.total === "number")) &&
       "has_active_game" in data &&
       typeof data.has_active_game === "boolean" &&
       "current_index" in data &&
@@ -68,9 +87,16 @@ export class ValidatorService {
       "options" in question &&
       Array.isArray(question.options) &&
       question.options.every((opt) => typeof opt === "string") &&
+      "text_html" in question &&
+      typeof question.text_html === "string" &&
+      "options_html" in question &&
+      Array.isArray(question.options_html) &&
+      question.options_html.every((opt) => typeof opt === "string") &&
       "time_limit" in question &&
       typeof question.time_limit === "number" &&
-      question.time_limit > 0
+      question.time_limit > 0 &&
+      (!("deadline" in question) ||
+        (typeof question.deadline === "string" && !Number.isNaN(Date.parse(question.deadline))))
     );
   }
 }
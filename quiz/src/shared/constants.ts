@@ -1,4 +1,6 @@
-export const BASE_URL = "http://localhost:5000/api/v1/quiz";
+export const API_URL = "http://localhost:5000/api/v1";
+
+export const BASE_URL = `${API_URL}/quiz`;
 
 export const TIMEOUT_DELAY = 2000;
 
@@ -22,10 +24,12 @@ export const responseStatuses = {
   timeout: "Time is up!",
   wrong_answer: "Wrong answer",
   correct: "Correct!",
+  recorded: "Answer recorded",
 };
 
 export const STATS = {
   correctAnswers: "Correct answers",
   incorrectAnswers: "Incorrect answers",
   currentQuestionNumber: "Total answers",
+  score: "Score",
 };
@@ -4,28 +4,59 @@ export interface IPostBody {
   question_id: number;
 }
 
-export type messageStatus = "timeout" | "wrong_answer" | "correct";
+export type messageStatus = "timeout" | "wrong_answer" | "correct" | "recorded";
 
 export interface IAnswer extends IResponse {
-  correct: boolean;
+  // Omitted when the quiz's feedback policy withholds results.
+  correct?: boolean;
   reason: messageStatus;
-  correct_answer_idx: number;
+  correct_answer_idx?: number;
+  feedback_withheld?: boolean;
+  explanation_html?: string;
+  references?: Array<IReference>;
+}
+
+export interface IReference {
+  title?: string;
+  url: string;
 }
 
 export interface IQuestion {
   id: number;
   text: string;
   options: Array<string>;
+  format: "plain" | "markdown";
+  text_html: string;
+  options_html: Array<string>;
   time_limit: number;
+  // When the answer is due, on the server clock.
+  deadline?: string;
+}
+
+export interface IScore {
+  policy: string;
+  total: number;
+  base: number;
+  speed_bonus: number;
+  streak_bonus: number;
+  penalty: number;
+  streak: number;
 }
 
 export interface IStats {
-  total_correct: number;
-  total_incorrect: number;
+  // Omitted when the quiz's feedback policy withholds results.
+  total_correct?: number;
+  total_incorrect?: number;
+  score?: IScore;
   has_active_game: boolean;
   current_index: number;
 }
 
+export interface IServerTime {
+  server_time: string;
+  unix_ms: number;
+}
+
 export interface IResponse extends IStats {
   next_question?: IQuestion;
 }